@@ -0,0 +1,61 @@
+package goargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Determine whether long flags can be abbreviated to any unambiguous prefix, as with getopt_long,
+e.g. `--verb` for `--verbose`. Off by default. An exact name always takes precedence over prefixes.
+Parse() returns an *AmbiguousFlagError if a prefix matches several flags.
+Sub-commands added afterwards inherit the setting.
+*/
+func (p *Parser) AllowAbbreviations(allow bool) {
+	p.allow_abbreviations = allow
+}
+
+/*
+Resolve a long flag name, an alias, or a `no-NAME` negation, or if allowed, an unambiguous prefix of any of these.
+Returns a nil definition if none matches, and whether the definition is negated.
+*/
+func (p *Parser) resolveLongFlag(longname string) (t_VarDef, bool, error) {
+	if def, ok := p.lookupFlag(longname); ok {
+		return def, false, nil
+	}
+	if def, ok := p.negatedBool(longname); ok {
+		return def, true, nil
+	}
+	if !p.allow_abbreviations || longname == "" {
+		return nil, false, nil
+	}
+
+	// matching names of the same flag, such as aliases, are not ambiguous
+	candidates := []string{}
+	matched := map[string]bool{}
+	var found t_VarDef
+	var negated bool
+	for _, name := range p.longnames {
+		for _, candidate := range p.completionLongNames(name) {
+			if !strings.HasPrefix(candidate, longname) {
+				continue
+			}
+			candidates = append(candidates, candidate)
+			def, isnegated, _ := p.resolveLongFlag(candidate)
+			key := fmt.Sprintf("%s %t", def.getName(), isnegated)
+			if !matched[key] {
+				matched[key] = true
+				found, negated = def, isnegated
+			}
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		return found, negated, nil
+	default:
+		return nil, false, &AmbiguousFlagError{longname, candidates}
+	}
+}
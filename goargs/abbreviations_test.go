@@ -0,0 +1,62 @@
+package goargs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Abbreviations(t *testing.T) {
+	parser := NewParser("")
+	parser.AllowAbbreviations(true)
+	parser.NegatableBools(true)
+	verbose := parser.Count("verbose", "")
+	version := parser.Bool("version", false, "")
+	color := parser.String("color", "auto", "")
+	parser.SetAlias("colour", "color")
+	parser.String("col", "", "")
+
+	if err := parser.Parse([]string{"--verb", "--vers", "--colo", "never"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, 1, *verbose)
+	gocheck.Equal(t, true, *version)
+	gocheck.Equal(t, "never", *color)
+
+	// exact matches take precedence
+	if err := parser.Parse([]string{"--col", "x", "--no-vers", "--verb=3"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "auto", *color)
+	gocheck.Equal(t, false, *version)
+	gocheck.Equal(t, 3, *verbose)
+
+	var ambiguous *AmbiguousFlagError
+	err := parser.Parse([]string{"--ver"})
+	if !errors.As(err, &ambiguous) {
+		t.Errorf("Expected AmbiguousFlagError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "ver", ambiguous.Flag)
+		gocheck.EqualArr(t, []string{"verbose", "version"}, ambiguous.Candidates)
+		gocheck.Equal(t, "ambiguous flag --ver could be: --verbose, --version", err.Error())
+	}
+
+	if err := parser.Parse([]string{"--co", "x"}); !errors.As(err, &ambiguous) {
+		t.Errorf("Expected AmbiguousFlagError, got: %v", err)
+	} else {
+		gocheck.EqualArr(t, []string{"color", "colour", "col"}, ambiguous.Candidates)
+	}
+}
+
+func Test_Abbreviations_Off(t *testing.T) {
+	parser := NewParser("")
+	parser.AllowAbbreviations(true)
+	parser.Count("verbose", "")
+	parser.AllowAbbreviations(false)
+
+	var unknown *UnknownFlagError
+	if err := parser.Parse([]string{"--verb"}); !errors.As(err, &unknown) {
+		t.Errorf("Expected UnknownFlagError, got: %v", err)
+	}
+}
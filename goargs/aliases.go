@@ -0,0 +1,69 @@
+package goargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Add another long name for an existing flag, such as `--dir` for `--directory`.
+Parse() and configuration files resolve the alias to the flag's definition,
+and help lists it after the flag's name, as `--directory, --dir`.
+Panics if the flag is not yet registered, or if the alias is invalid or already in use.
+*/
+func (p *Parser) SetAlias(alias string, longname string) {
+	if _, ok := p.definitions[longname]; !ok {
+		panic(fmt.Sprintf("Flag '--%s' not yet defined", longname))
+	}
+	if err := p.checkName(alias); err != nil {
+		panic(err.Error())
+	}
+	p.aliases[alias] = longname
+	p.aliasnames = append(p.aliasnames, alias)
+}
+
+// the definition of a long flag, by its name or one of its aliases
+func (p *Parser) lookupFlag(name string) (t_VarDef, bool) {
+	if longname, ok := p.aliases[name]; ok {
+		name = longname
+	}
+	def, ok := p.definitions[name]
+	return def, ok
+}
+
+// the long names of a flag: its name, then its aliases in the order they were added
+func (p *Parser) longNamesFor(name string) []string {
+	names := []string{name}
+	for _, alias := range p.aliasnames {
+		if p.aliases[alias] == name {
+			names = append(names, alias)
+		}
+	}
+	return names
+}
+
+// whether a flag can be set as `--no-NAME`
+func (p *Parser) isNegatable(name string) bool {
+	_, isbool := p.definitions[name].(def_Bool)
+	return isbool && p.negatable_bools
+}
+
+// the long flags of a flag as listed in help, e.g. "--directory, --dir", or "--[no-]color" for negatable Bool flags
+func (p *Parser) longFlagsLabel(name string) string {
+	prefix := "--"
+	if p.isNegatable(name) {
+		prefix = "--[no-]"
+	}
+	return prefix + strings.Join(p.longNamesFor(name), ", "+prefix)
+}
+
+// the long names to complete for a flag, including their negations
+func (p *Parser) completionLongNames(name string) []string {
+	names := p.longNamesFor(name)
+	if p.isNegatable(name) {
+		for _, long := range p.longNamesFor(name) {
+			names = append(names, "no-"+long)
+		}
+	}
+	return names
+}
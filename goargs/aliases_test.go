@@ -0,0 +1,86 @@
+package goargs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Alias_Parse(t *testing.T) {
+	parser := NewParser("tool")
+	directory := parser.String("directory", ".", "Where to work")
+	parser.SetShortFlag('d', "directory")
+	parser.SetAlias("dir", "directory")
+	parser.SetAlias("folder", "directory")
+
+	if err := parser.Parse([]string{"--dir", "/tmp"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "/tmp", *directory)
+
+	if err := parser.Parse([]string{"--folder=/var"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "/var", *directory)
+
+	if err := parser.ParseConfig(strings.NewReader("dir = /opt"), CONFIG_INI); err != nil {
+		t.Errorf("Failed config: %v", err)
+	}
+	if err := parser.Parse([]string{}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "/opt", *directory)
+}
+
+func Test_Alias_Help(t *testing.T) {
+	parser := NewParser("tool")
+	parser.String("directory", ".", "Where to work")
+	parser.SetShortFlag('d', "directory")
+	parser.SetAlias("dir", "directory")
+	parser.SetAlias("folder", "directory")
+
+	gocheck.EqualArr(t, []string{
+		"tool",
+		"",
+		"  --directory, --dir, --folder STRING",
+		"  -d STRING",
+		"    default: .",
+		"    Where to work",
+	}, strings.Split(parser.SPrintHelp(), "\n"))
+
+	parser.SetHelpLayout(HELP_LAYOUT_COMPACT)
+	parser.SetHelpWidth(80)
+	gocheck.EqualArr(t, []string{
+		"tool",
+		"",
+		"Options:",
+		"  -d, --directory, --dir, --folder STRING",
+		"                                Where to work (default: .)",
+	}, strings.Split(parser.SPrintHelp(), "\n"))
+
+	gocheck.EqualArr(t, []string{"--directory", "--dir"}, parser.Complete([]string{"--di"}))
+}
+
+func Test_Alias_Conflicts(t *testing.T) {
+	for _, setup := range []func(*Parser){
+		func(p *Parser) { p.SetAlias("directory", "directory") },
+		func(p *Parser) { p.SetAlias("dir", "directory") },
+		func(p *Parser) { p.String("dir", "", "Conflicting") },
+		func(p *Parser) { p.SetAlias("cwd", "nope") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Conflicting alias should have panicked")
+				}
+			}()
+			parser := NewParser("tool")
+			parser.String("directory", ".", "Where to work")
+			parser.SetShortFlag('d', "directory")
+			parser.SetAlias("dir", "directory")
+			parser.SetAlias("folder", "directory")
+			setup(&parser)
+		}()
+	}
+}
@@ -0,0 +1,290 @@
+package goargs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// keys recognised in `goargs:"..."` struct tags
+var _BIND_TAG_KEYS = []string{"name", "short", "help", "env", "choices", "mode", "prefix", "required", "count"}
+
+/*
+Register flags for each exported field of the struct pointed to by `structptr`.
+The current value of each field is used as its default value.
+
+Fields are configured with `goargs` tags, as comma-separated `key=value` items:
+
+	type Config struct {
+		Listen  string        `goargs:"name=listen,short=l,env=LISTEN,help=Address to listen on"`
+		Format  string        `goargs:"choices=text|json,help=Output format"`
+		Style   string        `goargs:"mode=b:bright|d:dark,help=Colour style"`
+		Verbose int           `goargs:"count,short=v,help=Verbosity"`
+		Token   string        `goargs:"required"`
+		Files   []string      `goargs:"help=Files to include"`
+		Timeout time.Duration
+		DB      DBConfig      `goargs:"prefix=database"`
+		Skipped string        `goargs:"-"`
+	}
+
+* name : the long flag name. Defaults to the field name in kebab-case (`ListenAddr` is `--listen-addr`)
+* short, help, env, required : like SetShortFlag(), the help string, SetEnvVar() and SetRequired()
+* choices : a `|`-separated list for a Choices flag on a string field (the first choice is the default)
+* mode : a `|`-separated list of `rune:value` for a Mode flag on a string field (not combinable with short)
+* count : use a Count flag on an int field
+* prefix : for a nested struct field, the prefix of its flags' names. Defaults to the field name in kebab-case,
+  so that `DB.Host` is `--db-host`. Embedded structs have no prefix.
+
+The help text should be the last item, as it may contain commas.
+Fields tagged with `goargs:"-"` are ignored.
+
+Supported field types are those supported by FlagVar(), and nested structs.
+Returns an error naming the field if a tag is invalid, a type is unsupported, or a name is already in use.
+*/
+func (p *Parser) BindStruct(structptr interface{}) error {
+	value := reflect.ValueOf(structptr)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindStruct requires a pointer to a struct, got %T", structptr)
+	}
+	return p.bindFields(value.Elem(), "", "")
+}
+
+func (p *Parser) bindFields(structval reflect.Value, prefix string, path string) error {
+	structtype := structval.Type()
+
+	for i := 0; i < structtype.NumField(); i++ {
+		field := structtype.Field(i)
+		// fields of embedded structs are promoted, even if the struct type is unexported
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		tag, hastag := field.Tag.Lookup("goargs")
+		if tag == "-" {
+			continue
+		}
+		fieldpath := path + field.Name
+
+		opts, err := parseBindTag(tag)
+		if err != nil {
+			return fmt.Errorf("field %s: %v", fieldpath, err)
+		}
+
+		fieldval := structval.Field(i)
+		if isNestedStruct(fieldval) {
+			subprefix := prefix
+			if optprefix, ok := opts["prefix"]; ok {
+				subprefix += optprefix + "-"
+			} else if !field.Anonymous || hastag {
+				subprefix += kebabCase(field.Name) + "-"
+			}
+			if err := p.bindFields(fieldval, subprefix, fieldpath+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := p.bindField(fieldval, prefix, field.Name, opts); err != nil {
+			return fmt.Errorf("field %s: %v", fieldpath, err)
+		}
+	}
+	return nil
+}
+
+// whether the field is a struct to recurse into, rather than a value type
+func isNestedStruct(fieldval reflect.Value) bool {
+	if fieldval.Kind() != reflect.Struct {
+		return false
+	}
+	ptrtype := reflect.PointerTo(fieldval.Type())
+	return !ptrtype.Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) && !ptrtype.Implements(reflect.TypeFor[Value]())
+}
+
+func (p *Parser) bindField(fieldval reflect.Value, prefix string, fieldname string, opts map[string]string) error {
+	name := prefix + kebabCase(fieldname)
+	if optname, ok := opts["name"]; ok {
+		name = prefix + optname
+	}
+	if err := p.checkName(name); err != nil {
+		return err
+	}
+
+	var short rune
+	if optshort, ok := opts["short"]; ok {
+		if utf8.RuneCountInString(optshort) != 1 {
+			return fmt.Errorf("short flag must be a single character, got '%s'", optshort)
+		}
+		short, _ = utf8.DecodeRuneInString(optshort)
+		if err := p.checkShortFlag(short); err != nil {
+			return err
+		}
+	}
+
+	helpstr := opts["help"]
+	ptr := fieldval.Addr().Interface()
+
+	if choices, ok := opts["choices"]; ok {
+		strptr, ok := ptr.(*string)
+		if !ok {
+			return fmt.Errorf("choices require a string field")
+		}
+		p.ChoicesVar(strptr, name, strings.Split(choices, "|"), helpstr)
+
+	} else if modespec, ok := opts["mode"]; ok {
+		strptr, ok := ptr.(*string)
+		if !ok {
+			return fmt.Errorf("mode requires a string field")
+		}
+		if short != 0 {
+			return fmt.Errorf("mode flags use their mode runes as short flags, and cannot also have a short flag")
+		}
+		modes, err := parseBindModes(modespec)
+		if err != nil {
+			return err
+		}
+		for r := range modes {
+			if err := p.checkShortFlag(r); err != nil {
+				return err
+			}
+		}
+		p.ModeVar(strptr, name, *strptr, modes, helpstr)
+
+	} else if _, ok := opts["count"]; ok {
+		intptr, ok := ptr.(*int)
+		if !ok {
+			return fmt.Errorf("count requires an int field")
+		}
+		p.CountVar(intptr, name, helpstr)
+
+	} else if err := p.bindValue(ptr, name, helpstr); err != nil {
+		return err
+	}
+
+	if short != 0 {
+		p.SetShortFlag(short, name)
+	}
+	if envname, ok := opts["env"]; ok {
+		p.SetEnvVar(name, envname)
+	}
+	if _, ok := opts["required"]; ok {
+		p.SetRequired(name)
+	}
+	return nil
+}
+
+// register a flag by the type of the field pointer, with the field's current value as default
+func (p *Parser) bindValue(ptr interface{}, name string, helpstr string) error {
+	switch fieldptr := ptr.(type) {
+	case *string:
+		p.StringVar(fieldptr, name, *fieldptr, helpstr)
+	case *int:
+		p.IntVar(fieldptr, name, *fieldptr, helpstr)
+	case *int64:
+		p.Int64Var(fieldptr, name, *fieldptr, helpstr)
+	case *uint:
+		p.UintVar(fieldptr, name, *fieldptr, helpstr)
+	case *float32:
+		p.FloatVar(fieldptr, name, *fieldptr, helpstr)
+	case *float64:
+		p.Float64Var(fieldptr, name, *fieldptr, helpstr)
+	case *bool:
+		p.BoolVar(fieldptr, name, *fieldptr, helpstr)
+	case *time.Duration:
+		p.DurationVar(fieldptr, name, *fieldptr, helpstr)
+	case *[]string:
+		p.AppenderVar(fieldptr, name, helpstr)
+	case encoding.TextUnmarshaler:
+		marshaler, ok := reflect.ValueOf(ptr).Elem().Interface().(encoding.TextMarshaler)
+		if !ok {
+			marshaler, ok = ptr.(encoding.TextMarshaler)
+		}
+		if !ok {
+			return fmt.Errorf("type %T implements encoding.TextUnmarshaler but not encoding.TextMarshaler", ptr)
+		}
+		if _, err := marshaler.MarshalText(); err != nil {
+			return fmt.Errorf("invalid default value: %v", err)
+		}
+		p.TextVar(fieldptr, name, marshaler, helpstr)
+	case Value:
+		p.Var(fieldptr, name, helpstr)
+	default:
+		return fmt.Errorf("unsupported type %T", ptr)
+	}
+	return nil
+}
+
+// Parse a `goargs` tag into its items. Items without a known `key=` prefix are
+// continuations of the previous item's value, so that help strings can contain commas.
+func parseBindTag(tag string) (map[string]string, error) {
+	opts := make(map[string]string)
+	if tag == "" {
+		return opts, nil
+	}
+
+	lastkey := ""
+	for _, item := range strings.Split(tag, ",") {
+		key, value, hasvalue := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+		if !slices.Contains(_BIND_TAG_KEYS, key) {
+			if lastkey == "" {
+				return nil, fmt.Errorf("unknown tag item '%s'", item)
+			}
+			opts[lastkey] += "," + item
+			continue
+		}
+		switch key {
+		case "required", "count":
+			if hasvalue {
+				return nil, fmt.Errorf("tag item '%s' does not take a value", key)
+			}
+		default:
+			if !hasvalue {
+				return nil, fmt.Errorf("tag item '%s' requires a value", key)
+			}
+		}
+		if _, ok := opts[key]; ok {
+			return nil, fmt.Errorf("duplicate tag item '%s'", key)
+		}
+		opts[key] = value
+		lastkey = key
+	}
+	return opts, nil
+}
+
+// Parse a mode specification like `b:bright|d:dark`
+func parseBindModes(spec string) (map[rune]string, error) {
+	modes := make(map[rune]string)
+	for _, item := range strings.Split(spec, "|") {
+		short, value, found := strings.Cut(item, ":")
+		if !found || utf8.RuneCountInString(short) != 1 || value == "" {
+			return nil, fmt.Errorf("invalid mode '%s', expected 'r:value'", item)
+		}
+		r, _ := utf8.DecodeRuneInString(short)
+		if _, ok := modes[r]; ok {
+			return nil, fmt.Errorf("duplicate mode rune '%c'", r)
+		}
+		modes[r] = value
+	}
+	return modes, nil
+}
+
+// Convert a Go field name to kebab-case, e.g. `ListenAddr` to `listen-addr` and `DBHost` to `db-host`
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var out []rune
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				out = append(out, '-')
+			}
+		}
+		out = append(out, unicode.ToLower(r))
+	}
+	return string(out)
+}
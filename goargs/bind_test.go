@@ -0,0 +1,125 @@
+package goargs
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taikedz/gocheck"
+)
+
+type t_DBConfig struct {
+	Host string `goargs:"help=Database host"`
+	Port int
+}
+
+type t_Logging struct {
+	LogLevel slog.Level `goargs:"name=log-level,help=Logging level"`
+}
+
+type t_BindConfig struct {
+	Listen  string        `goargs:"name=listen,short=l,env=BIND_LISTEN,help=Address to listen on, with port"`
+	Format  string        `goargs:"choices=text|json,help=Output format"`
+	Style   string        `goargs:"mode=b:bright|d:dark"`
+	Verbose int           `goargs:"count,short=v"`
+	Token   string        `goargs:"required"`
+	Files   []string      `goargs:"name=file"`
+	Timeout time.Duration `goargs:"help=Time to wait"`
+	DB      t_DBConfig
+	Backup  t_DBConfig `goargs:"prefix=bk"`
+	t_Logging
+	Skipped  string `goargs:"-"`
+	internal string
+}
+
+func Test_BindStruct(t *testing.T) {
+	t.Setenv("BIND_LISTEN", "0.0.0.0:80")
+
+	cfg := t_BindConfig{Style: "dark", Timeout: time.Second}
+	cfg.DB.Port = 5432
+	parser := NewParser("")
+	if err := parser.BindStruct(&cfg); err != nil {
+		t.Errorf("Failed bind: %v", err)
+		return
+	}
+
+	gocheck.EqualArr(t, []string{"listen", "format", "style", "verbose", "token", "file", "timeout",
+		"db-host", "db-port", "bk-host", "bk-port", "log-level"}, parser.longnames)
+	gocheck.Equal(t, "Address to listen on, with port", parser.definitions["listen"].getHelpString())
+	gocheck.Equal(t, "text", cfg.Format)
+
+	args := []string{"--format", "json", "-b", "-vv", "--token", "abc", "--file", "a", "--file", "b",
+		"--db-host", "db.local", "--bk-port", "6543", "--log-level", "debug"}
+	if err := parser.Parse(args); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, "0.0.0.0:80", cfg.Listen)
+	gocheck.Equal(t, "json", cfg.Format)
+	gocheck.Equal(t, "bright", cfg.Style)
+	gocheck.Equal(t, 2, cfg.Verbose)
+	gocheck.Equal(t, "abc", cfg.Token)
+	gocheck.EqualArr(t, []string{"a", "b"}, cfg.Files)
+	gocheck.Equal(t, time.Second, cfg.Timeout)
+	gocheck.Equal(t, "db.local", cfg.DB.Host)
+	gocheck.Equal(t, 5432, cfg.DB.Port)
+	gocheck.Equal(t, 6543, cfg.Backup.Port)
+	gocheck.Equal(t, slog.LevelDebug, cfg.LogLevel)
+
+	if err := parser.Parse([]string{}); err == nil {
+		t.Errorf("Required token should have been enforced")
+	}
+}
+
+func Test_BindStruct_Errors(t *testing.T) {
+	cases := map[string]interface{}{
+		"field Bad: unknown tag item 'colour=red'": &struct {
+			Bad string `goargs:"colour=red"`
+		}{},
+		"field Bad: short flag must be a single character, got 'xy'": &struct {
+			Bad string `goargs:"short=xy"`
+		}{},
+		"field Bad: choices require a string field": &struct {
+			Bad int `goargs:"choices=a|b"`
+		}{},
+		"field Bad: mode flags use their mode runes as short flags, and cannot also have a short flag": &struct {
+			Bad string `goargs:"short=b,mode=b:bright|d:dark"`
+		}{},
+		"field Bad: unsupported type *complex128": &struct {
+			Bad complex128
+		}{},
+		"field Second: Flag '--first' already defined.": &struct {
+			First  string
+			Second string `goargs:"name=first"`
+		}{},
+		"field Nested.Bad: tag item 'count' does not take a value": &struct {
+			Nested struct {
+				Bad int `goargs:"count=1"`
+			}
+		}{},
+	}
+
+	for expect, target := range cases {
+		parser := NewParser("")
+		err := parser.BindStruct(target)
+		if err == nil {
+			t.Errorf("Expected error '%s'", expect)
+		} else if !strings.Contains(err.Error(), expect) {
+			t.Errorf("Expected error '%s', got '%v'", expect, err)
+		}
+	}
+
+	parser := NewParser("")
+	if err := parser.BindStruct(t_BindConfig{}); err == nil {
+		t.Errorf("Non-pointer should have failed")
+	}
+}
+
+func Test_kebabCase(t *testing.T) {
+	gocheck.Equal(t, "listen-addr", kebabCase("ListenAddr"))
+	gocheck.Equal(t, "db-host", kebabCase("DBHost"))
+	gocheck.Equal(t, "url", kebabCase("URL"))
+	gocheck.Equal(t, "http-server", kebabCase("HTTPServer"))
+}
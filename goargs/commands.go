@@ -0,0 +1,79 @@
+package goargs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+/*
+Register a sub-command verb, and return the Parser that handles its own flags and arguments.
+
+When parsing, the first positional token matching a registered verb selects the sub-command,
+and all tokens after it (including any `--` passdowns) are parsed by the sub-command's parser.
+Flags of this parser are therefore only recognised before the verb, and flags of the sub-command
+only after it.
+
+Sub-commands can themselves have sub-commands, e.g. `tool db migrate --dry-run`.
+
+Panics if the verb is already registered, or if it is not a valid name.
+*/
+func (p *Parser) AddCommand(name string, helptext string) *Parser {
+	if _, ok := p.commands[name]; ok {
+		panic(fmt.Sprintf("Command '%s' already defined.", name))
+	}
+	if matched, _ := regexp.MatchString("^[a-zA-Z][a-zA-Z0-9_-]*$", name); !matched {
+		panic(fmt.Sprintf("Invalid command name '%s'. Must start with a letter", name))
+	}
+
+	sub := NewParser(helptext)
+	sub.require_flagdefs = p.require_flagdefs
+	sub.env_prefix = p.env_prefix
+	sub.warning_output = p.warning_output
+	sub.negatable_bools = p.negatable_bools
+	sub.suggestion_distance = p.suggestion_distance
+	sub.allow_abbreviations = p.allow_abbreviations
+	sub.accumulate = p.accumulate
+	if p.program_name != "" {
+		sub.program_name = p.program_name + " " + name
+	}
+	sub.is_command = true
+	sub.help_layout = p.help_layout
+	sub.help_width = p.help_width
+	p.commands[name] = &sub
+	p.commandnames = append(p.commandnames, name)
+	return &sub
+}
+
+// Command returns the parser of the sub-command selected during the last parse,
+// or nil if no sub-command was selected.
+func (p *Parser) Command() *Parser {
+	return p.selected
+}
+
+// CommandPath returns the verbs of the sub-commands selected during the last parse,
+// outermost first. e.g. `[]string{"db", "migrate"}` for `tool db migrate --dry-run`
+// The returned slice is empty if no sub-command was selected.
+func (p *Parser) CommandPath() []string {
+	path := []string{}
+	for cur := p; cur.selected != nil; cur = cur.selected {
+		path = append(path, cur.selected_name)
+	}
+	return path
+}
+
+// help lines listing the sub-commands, with the first line of each sub-command's help text
+func (p *Parser) commandHelpLines() []string {
+	if len(p.commandnames) == 0 {
+		return []string{}
+	}
+
+	helplines := []string{"", "Commands:"}
+	for _, name := range p.commandnames {
+		helplines = append(helplines, fmt.Sprintf("  %s", name))
+		if summary := strings.SplitN(p.commands[name].helptext, "\n", 2)[0]; summary != "" {
+			helplines = append(helplines, fmt.Sprintf("    %s", summary))
+		}
+	}
+	return helplines
+}
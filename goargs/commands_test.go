@@ -0,0 +1,95 @@
+package goargs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Commands(t *testing.T) {
+	parser := NewParser("tool {db|user} ...")
+	verbose := parser.Count("verbose", "Verbosity")
+	parser.SetShortFlag('v', "verbose")
+
+	db := parser.AddCommand("db", "Database operations")
+	migrate := db.AddCommand("migrate", "Apply migrations")
+	dryrun := migrate.Bool("dry-run", false, "Only print the migrations")
+
+	user := parser.AddCommand("user", "User operations")
+	user.Count("verbose", "User-level verbosity")
+
+	if err := parser.Parse([]string{"-v", "db", "migrate", "--dry-run", "extra", "--", "raw"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, 1, *verbose)
+	gocheck.Equal(t, true, *dryrun)
+	gocheck.EqualArr(t, []string{"db", "migrate"}, parser.CommandPath())
+	gocheck.Equal(t, db, parser.Command())
+	gocheck.Equal(t, migrate, db.Command())
+	gocheck.EqualArr(t, []string{}, parser.Args())
+	gocheck.EqualArr(t, []string{}, parser.ExtraArgs())
+	gocheck.EqualArr(t, []string{"extra"}, migrate.Args())
+	gocheck.EqualArr(t, []string{"raw"}, migrate.ExtraArgs())
+
+	// Parent flags are not known to the sub-command
+	if err := parser.Parse([]string{"db", "migrate", "-v"}); err == nil {
+		t.Errorf("Parent flag should not be accepted after the verb")
+	}
+}
+
+func Test_Commands_NotFirstPositional(t *testing.T) {
+	parser := NewParser("tool")
+	parser.AddCommand("run", "Run it")
+
+	if err := parser.Parse([]string{"file", "run"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, nil, parser.Command())
+	gocheck.EqualArr(t, []string{}, parser.CommandPath())
+	gocheck.EqualArr(t, []string{"file", "run"}, parser.Args())
+}
+
+func Test_Commands_Help(t *testing.T) {
+	parser := NewParser("tool")
+	parser.AddCommand("db", "Database operations\nMore details")
+	parser.AddCommand("user", "")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"tool",
+		"",
+		"",
+		"Commands:",
+		"  db",
+		"    Database operations",
+		"  user",
+	}, "\n")
+	if helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
+
+func Test_Commands_Inherit(t *testing.T) {
+	t.Setenv("TOOL_DRY_RUN", "true")
+
+	parser := NewParser("tool")
+	parser.SetEnvPrefix("TOOL_")
+	var out bytes.Buffer
+	parser.SetWarningOutput(&out)
+	migrate := parser.AddCommand("migrate", "Migrate the database")
+	dryrun := migrate.Bool("dry-run", false, "Only print the changes")
+	migrate.Bool("force", false, "Ignore errors")
+	migrate.SetDeprecated("force", "", "")
+
+	if err := parser.Parse([]string{"migrate", "--force"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, true, *dryrun)
+	gocheck.Equal(t, "Flag --force is deprecated\n", out.String())
+}
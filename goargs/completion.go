@@ -0,0 +1,493 @@
+package goargs
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// the short flag runes of a long flag, sorted (Mode flags have several)
+func (p *Parser) shortFlagsFor(name string) []rune {
+	shorts := []rune{}
+	for char, def := range p.shortnames {
+		if def.getName() == name {
+			shorts = append(shorts, char)
+		}
+	}
+	slices.Sort(shorts)
+	return shorts
+}
+
+// the fixed set of values a definition accepts, if any
+func fixedValues(def t_VarDef) []string {
+	switch def.(type) {
+	case def_Choices:
+		return def.(def_Choices).choices
+	case def_Mode:
+		values := []string{}
+		for _, value := range def.(def_Mode).modes {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		return values
+	}
+	return nil
+}
+
+// whether a definition takes a value when specified by its long name
+func takesValue(def t_VarDef) bool {
+	switch def.(type) {
+	case def_Bool, def_Count:
+		return false
+	}
+	return true
+}
+
+// the first line of a definition's help string
+func helpSummary(def t_VarDef) string {
+	return strings.SplitN(def.getHelpString(), "\n", 2)[0]
+}
+
+// call `fn` on this parser and on each nested sub-command parser, with its verb path, depth first
+func (p *Parser) walkCommands(path []string, fn func([]string, *Parser)) {
+	fn(path, p)
+	for _, verb := range p.commandnames {
+		p.commands[verb].walkCommands(append(slices.Clone(path), verb), fn)
+	}
+}
+
+// a shell function name segment from an arbitrary string
+func shellIdent(name string) string {
+	return regexp.MustCompile("[^a-zA-Z0-9_]").ReplaceAllString(name, "_")
+}
+
+// quote a string for POSIX shells and fish, as a single-quoted word
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+/*
+Produce a bash completion script for the program `progname`, and return it.
+The script completes flag names, values of Choices and Mode flags, and sub-command verbs.
+Values with a completer (see SetCompleter()) are completed by calling the program with COMPLETE_TOKEN.
+Other values and positionals complete as file names.
+
+e.g. `source <(mytool --bash-completion)` after printing the script from `mytool`
+*/
+func (p *Parser) SPrintBashCompletion(progname string) string {
+	funcname := fmt.Sprintf("_%s_completion", shellIdent(progname))
+	lines := []string{
+		fmt.Sprintf("# bash completion for %s", progname),
+		fmt.Sprintf("%s() {", funcname),
+		`    local cur="${COMP_WORDS[COMP_CWORD]}"`,
+		`    local prev="${COMP_WORDS[COMP_CWORD-1]}"`,
+		`    local path=""`,
+	}
+
+	if len(p.commandnames) > 0 {
+		// find the sub-command path as Parse() does: skip flags and their values, and stop at the first positional
+		shortvalues := []string{}
+		p.walkCommands(nil, func(path []string, cp *Parser) {
+			if pattern := cp.bashShortValuePattern(); pattern != "" {
+				shortvalues = append(shortvalues, strings.Join(path, " ")+":"+pattern)
+			}
+		})
+		lines = append(lines, `    local i word skip=0 positional=0`)
+		if len(shortvalues) > 0 {
+			lines = append(lines, fmt.Sprintf(`    local shortvalue=%s`, shellQuote("^("+strings.Join(shortvalues, "|")+")$")))
+		}
+		lines = append(lines,
+			`    for ((i = 1; i < COMP_CWORD; i++)); do`,
+			`        word="${COMP_WORDS[i]}"`,
+			`        if ((skip)); then`,
+			`            [[ "$word" == "=" ]] || skip=0`,
+			`            continue`,
+			`        fi`,
+		)
+		if len(shortvalues) > 0 {
+			lines = append(lines,
+				`        if [[ "${path}:${word}" =~ $shortvalue ]]; then`,
+				`            skip=1`,
+				`            continue`,
+				`        fi`,
+			)
+		}
+		lines = append(lines, `        case "${path}:${word}" in`)
+		p.walkCommands(nil, func(path []string, cp *Parser) {
+			prefix := strings.Join(path, " ") + ":"
+			if longs := cp.nextValueLongFlags(); len(longs) > 0 {
+				patterns := []string{}
+				for _, long := range longs {
+					patterns = append(patterns, shellQuote(prefix+long))
+				}
+				lines = append(lines, fmt.Sprintf(`            %s) skip=1 ;;`, strings.Join(patterns, "|")))
+			}
+			for _, verb := range cp.commandnames {
+				subpath := strings.Join(append(slices.Clone(path), verb), " ")
+				lines = append(lines, fmt.Sprintf(`            %s) path=%s ;;`, shellQuote(prefix+verb), shellQuote(subpath)))
+			}
+		})
+		lines = append(lines,
+			`            *:=) skip=1 ;;`,
+			`            *:--) positional=1; break ;;`,
+			`            *:-*) ;;`,
+			`            *) positional=1; break ;;`,
+			`        esac`,
+			`    done`,
+		)
+	}
+
+	lines = append(lines, "", `    case "$path" in`)
+	p.walkCommands(nil, func(path []string, cp *Parser) {
+		lines = append(lines, fmt.Sprintf(`        %s)`, shellQuote(strings.Join(path, " "))))
+		lines = append(lines, cp.bashCompletionLines()...)
+		lines = append(lines, `            ;;`)
+	})
+	lines = append(lines,
+		`    esac`,
+		`}`,
+		fmt.Sprintf("complete -F %s %s", funcname, progname),
+	)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// the long flags, including aliases, whose value is the next word
+func (p *Parser) nextValueLongFlags() []string {
+	longs := []string{}
+	for _, name := range p.longnames {
+		if _, ok := p.implicit_values[name]; ok || !takesValue(p.definitions[name]) {
+			continue
+		}
+		for _, long := range p.longNamesFor(name) {
+			longs = append(longs, "--"+long)
+		}
+	}
+	return longs
+}
+
+// a regular expression matching short flag clusters whose value is the next word, as in `-vaN VALUE`,
+// or the empty string if no short flag takes a value
+func (p *Parser) bashShortValuePattern() string {
+	switches := ""
+	values := ""
+	for _, char := range _VALID_SFLAGS {
+		def, ok := p.shortnames[char]
+		if !ok {
+			continue
+		}
+		switch def.(type) {
+		case def_Bool, def_Count, def_Mode:
+			switches += string(char)
+		default:
+			if _, implicit := p.implicit_values[def.getName()]; !implicit {
+				values += string(char)
+			}
+		}
+	}
+	if values == "" {
+		return ""
+	} else if switches == "" {
+		return fmt.Sprintf("-[%s]", values)
+	}
+	return fmt.Sprintf("-[%s]*[%s]", switches, values)
+}
+
+// bash statement completing from the program's candidates for the words up to the cursor
+const _BASH_DYNAMIC = `COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" ` + COMPLETE_TOKEN + ` "${COMP_WORDS[@]:1:COMP_CWORD}")" -- "$cur"))`
+
+// the bash completion case body for a single parser
+func (p *Parser) bashCompletionLines() []string {
+	lines := []string{}
+	flagwords := []string{}
+	valuecases := []string{}
+
+	for _, name := range p.listedNames() {
+		def := p.definitions[name]
+		longs := []string{}
+		for _, long := range p.completionLongNames(name) {
+			longs = append(longs, "--"+long)
+		}
+		flagwords = append(flagwords, longs...)
+		shorts := []string{}
+		for _, short := range p.shortFlagsFor(name) {
+			shorts = append(shorts, fmt.Sprintf("-%c", short))
+		}
+		flagwords = append(flagwords, shorts...)
+
+		if _, ok := p.implicit_values[name]; ok || !takesValue(def) {
+			// the value of flags with an implicit value is never the next word
+			continue
+		}
+		patterns := strings.Join(longs, "|")
+		if _, ismode := def.(def_Mode); !ismode && len(shorts) > 0 {
+			patterns += "|" + strings.Join(shorts, "|")
+		}
+		if _, ok := p.completers[name]; ok {
+			valuecases = append(valuecases, fmt.Sprintf(`                %s) %s; return ;;`, patterns, _BASH_DYNAMIC))
+		} else if values := fixedValues(def); values != nil {
+			valuecases = append(valuecases, fmt.Sprintf(`                %s) COMPREPLY=($(compgen -W %s -- "$cur")); return ;;`, patterns, shellQuote(strings.Join(values, " "))))
+		} else {
+			valuecases = append(valuecases, fmt.Sprintf(`                %s) COMPREPLY=($(compgen -f -- "$cur")); return ;;`, patterns))
+		}
+	}
+
+	if len(valuecases) > 0 {
+		lines = append(lines, `            case "$prev" in`)
+		lines = append(lines, valuecases...)
+		lines = append(lines, `            esac`)
+	}
+
+	lines = append(lines,
+		`            if [[ "$cur" == -* ]]; then`,
+		fmt.Sprintf(`                COMPREPLY=($(compgen -W %s -- "$cur"))`, shellQuote(strings.Join(flagwords, " "))),
+	)
+	if len(p.commandnames) > 0 {
+		// verbs are only recognised before the first positional
+		lines = append(lines,
+			`            elif ((!positional)); then`,
+			fmt.Sprintf(`                COMPREPLY=($(compgen -W %s -- "$cur"))`, shellQuote(strings.Join(p.commandnames, " "))),
+		)
+	}
+	lines = append(lines, `            else`)
+	if p.hasPositionalCompleters() {
+		lines = append(lines, "                "+_BASH_DYNAMIC)
+	} else {
+		lines = append(lines, `                COMPREPLY=($(compgen -f -- "$cur"))`)
+	}
+	lines = append(lines, `            fi`)
+	return lines
+}
+
+// escape text for use in a zsh _arguments specification
+func zshEscape(text string) string {
+	return strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(text)
+}
+
+/*
+Produce a zsh completion script for the program `progname`, and return it.
+The script completes flag names with their help, values of Choices and Mode flags,
+sub-command verbs, and declared positionals by name.
+Values with a completer (see SetCompleter()) are completed by calling the program with COMPLETE_TOKEN.
+
+e.g. save the script as `_mytool` in a directory on `$fpath`
+*/
+func (p *Parser) SPrintZshCompletion(progname string) string {
+	lines := []string{fmt.Sprintf("#compdef %s", progname)}
+	dynamic := fmt.Sprintf("_%s__dynamic", shellIdent(progname))
+
+	if p.hasCompleters() {
+		lines = append(lines,
+			"",
+			fmt.Sprintf("%s() {", dynamic),
+			`    local -a tokens`,
+			`    tokens=(${(Q)${(z)LBUFFER}})`,
+			`    [[ $LBUFFER == *' ' ]] && tokens+=('')`,
+			fmt.Sprintf(`    compadd -- ${(f)"$($tokens[1] %s "${(@)tokens[2,-1]}")"}`, COMPLETE_TOKEN),
+			"}",
+		)
+	}
+
+	p.walkCommands(nil, func(path []string, cp *Parser) {
+		funcname := "_" + shellIdent(strings.Join(append([]string{progname}, path...), "_"))
+		lines = append(lines, "", fmt.Sprintf("%s() {", funcname))
+
+		specs := cp.zshArgumentSpecs(dynamic)
+		if len(cp.commandnames) > 0 {
+			lines = append(lines, `    local context state state_descr line`, `    typeset -A opt_args`)
+			specs = append(specs, `'1: :->command'`, `'*:: :->args'`)
+			lines = append(lines, `    _arguments -C -s \`)
+		} else {
+			lines = append(lines, `    _arguments -s \`)
+		}
+		for i, spec := range specs {
+			if i < len(specs)-1 {
+				spec += ` \`
+			}
+			lines = append(lines, "        "+spec)
+		}
+
+		if len(cp.commandnames) > 0 {
+			lines = append(lines,
+				`    case $state in`,
+				`        command)`,
+				`            local -a commands`,
+				`            commands=(`,
+			)
+			for _, verb := range cp.commandnames {
+				summary := strings.SplitN(cp.commands[verb].helptext, "\n", 2)[0]
+				lines = append(lines, fmt.Sprintf(`                '%s:%s'`, verb, strings.NewReplacer("'", `'\''`, ":", `\:`).Replace(summary)))
+			}
+			lines = append(lines,
+				`            )`,
+				`            _describe 'command' commands`,
+				`            ;;`,
+				`        args)`,
+				`            case $line[1] in`,
+			)
+			for _, verb := range cp.commandnames {
+				subfunc := "_" + shellIdent(strings.Join(append([]string{progname}, append(slices.Clone(path), verb)...), "_"))
+				lines = append(lines, fmt.Sprintf(`                %s) %s ;;`, verb, subfunc))
+			}
+			lines = append(lines, `            esac`, `            ;;`, `    esac`)
+		}
+		lines = append(lines, "}")
+	})
+
+	lines = append(lines, "", fmt.Sprintf(`_%s "$@"`, shellIdent(progname)))
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// the zsh _arguments specifications of a single parser's flags and positionals,
+// using the `dynamic` function for values with a completer
+func (p *Parser) zshArgumentSpecs(dynamic string) []string {
+	specs := []string{}
+	for _, name := range p.listedNames() {
+		def := p.definitions[name]
+		help := zshEscape(helpSummary(def))
+
+		repeat := ""
+		switch def.(type) {
+		case def_Count, def_Appender, def_Func:
+			repeat = "*"
+		}
+
+		action := ""
+		if takesValue(def) {
+			if _, ok := p.completers[name]; ok {
+				action = fmt.Sprintf(":%s:{%s}", valueTypeName(def), dynamic)
+			} else if values := fixedValues(def); values != nil {
+				action = fmt.Sprintf(":%s:(%s)", valueTypeName(def), zshEscape(strings.Join(values, " ")))
+			} else {
+				action = fmt.Sprintf(":%s:_files", valueTypeName(def))
+			}
+		}
+
+		if mode, ismode := def.(def_Mode); ismode {
+			for _, long := range p.completionLongNames(name) {
+				specs = append(specs, fmt.Sprintf(`'--%s[%s]%s'`, long, help, action))
+			}
+			for _, short := range p.shortFlagsFor(name) {
+				specs = append(specs, fmt.Sprintf(`'-%c[%s]'`, short, zshEscape(mode.modes[short])))
+			}
+			continue
+		}
+
+		// an optional value can only be attached to the flag
+		longsuffix, shortsuffix := "", ""
+		if _, ok := p.implicit_values[name]; ok && action != "" {
+			longsuffix, shortsuffix = "=-", "-"
+		}
+		for _, long := range p.completionLongNames(name) {
+			specs = append(specs, fmt.Sprintf(`'%s--%s%s[%s]%s'`, repeat, long, longsuffix, help, action))
+		}
+		for _, short := range p.shortFlagsFor(name) {
+			specs = append(specs, fmt.Sprintf(`'%s-%c%s[%s]%s'`, repeat, short, shortsuffix, help, action))
+		}
+	}
+
+	if len(p.commandnames) > 0 {
+		return specs
+	}
+	for i, pdef := range p.positional_defs {
+		position := fmt.Sprintf("%d", i+1)
+		if pdef.variadic {
+			position = "*"
+		}
+		optional := ""
+		if pdef.optional && !pdef.variadic {
+			optional = ":"
+		}
+		action := "_files"
+		if _, ok := p.completers[pdef.def.getName()]; ok {
+			action = fmt.Sprintf("{%s}", dynamic)
+		} else if values := fixedValues(pdef.def); values != nil {
+			action = fmt.Sprintf("(%s)", zshEscape(strings.Join(values, " ")))
+		}
+		specs = append(specs, fmt.Sprintf(`'%s:%s%s:%s'`, position, optional, zshEscape(pdef.def.getName()), action))
+	}
+	if len(p.positional_defs) == 0 {
+		specs = append(specs, `'*:file:_files'`)
+	}
+	return specs
+}
+
+/*
+Produce a fish completion script for the program `progname`, and return it.
+The script completes flag names with their help, values of Choices and Mode flags,
+sub-command verbs, and declared positionals by name.
+Values with a completer (see SetCompleter()) are completed by calling the program with COMPLETE_TOKEN.
+
+e.g. save the script as `~/.config/fish/completions/mytool.fish`
+*/
+func (p *Parser) SPrintFishCompletion(progname string) string {
+	lines := []string{fmt.Sprintf("# fish completion for %s", progname)}
+	dynamic := shellQuote(fmt.Sprintf("(%s %s (commandline -opc)[2..-1] (commandline -ct))", progname, COMPLETE_TOKEN))
+
+	p.walkCommands(nil, func(path []string, cp *Parser) {
+		// the parser's flags apply once its path is seen, and until one of its verbs is seen
+		conditions := []string{}
+		if len(path) == 0 && len(cp.commandnames) > 0 {
+			conditions = append(conditions, "__fish_use_subcommand")
+		}
+		for _, verb := range path {
+			conditions = append(conditions, "__fish_seen_subcommand_from "+verb)
+		}
+		if len(path) > 0 && len(cp.commandnames) > 0 {
+			conditions = append(conditions, "not __fish_seen_subcommand_from "+strings.Join(cp.commandnames, " "))
+		}
+		prefix := fmt.Sprintf("complete -c %s", progname)
+		if len(conditions) > 0 {
+			prefix += " -n " + shellQuote(strings.Join(conditions, "; and "))
+		}
+
+		for _, verb := range cp.commandnames {
+			summary := strings.SplitN(cp.commands[verb].helptext, "\n", 2)[0]
+			lines = append(lines, fmt.Sprintf("%s -f -a %s -d %s", prefix, verb, shellQuote(summary)))
+		}
+
+		for _, name := range cp.listedNames() {
+			def := cp.definitions[name]
+			line := prefix
+			for _, long := range cp.completionLongNames(name) {
+				line += " -l " + long
+			}
+			mode, ismode := def.(def_Mode)
+			if !ismode {
+				for _, short := range cp.shortFlagsFor(name) {
+					line += fmt.Sprintf(" -s %c", short)
+				}
+			}
+			line += " -d " + shellQuote(helpSummary(def))
+			if takesValue(def) {
+				if _, ok := cp.completers[name]; ok {
+					line += " -x -a " + dynamic
+				} else if values := fixedValues(def); values != nil {
+					line += " -x -a " + shellQuote(strings.Join(values, " "))
+				} else {
+					line += " -r"
+				}
+			}
+			lines = append(lines, line)
+
+			if ismode {
+				for _, short := range cp.shortFlagsFor(name) {
+					lines = append(lines, fmt.Sprintf("%s -s %c -d %s", prefix, short, shellQuote(mode.modes[short])))
+				}
+			}
+		}
+
+		if len(cp.commandnames) == 0 {
+			for _, pdef := range cp.positional_defs {
+				if _, ok := cp.completers[pdef.def.getName()]; ok {
+					lines = append(lines, fmt.Sprintf("%s -f -a %s -d %s", prefix, dynamic, shellQuote(pdef.def.getName())))
+				} else if values := fixedValues(pdef.def); values != nil {
+					lines = append(lines, fmt.Sprintf("%s -f -a %s -d %s", prefix, shellQuote(strings.Join(values, " ")), shellQuote(pdef.def.getName())))
+				}
+			}
+		}
+	})
+
+	return strings.Join(lines, "\n") + "\n"
+}
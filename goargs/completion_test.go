@@ -0,0 +1,178 @@
+package goargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func compareCompletion(t *testing.T, got string, expect []string) {
+	t.Helper()
+	if want := strings.Join(expect, "\n") + "\n"; got != want {
+		t.Errorf("Mismatched completion. Got:\n<<%s>>\nInstead of:\n<<%s>>", got, want)
+	}
+}
+
+func Test_BashCompletion(t *testing.T) {
+	parser := NewParser("tool")
+	parser.Count("verbose", "Be verbose")
+	parser.SetShortFlag('v', "verbose")
+	parser.Choices("format", []string{"text", "json"}, "Output format")
+	parser.SetShortFlag('f', "format")
+	parser.Mode("style", "bright", map[rune]string{'b': "bright", 'd': "dark"}, "Colour style")
+
+	user := parser.AddCommand("user", "User operations")
+	user.String("home", "", "Home directory")
+	user.PositionalChoices("ACTION", []string{"add", "del"}, "What to do")
+	compareCompletion(t, parser.SPrintBashCompletion("tool"), []string{
+		`# bash completion for tool`,
+		`_tool_completion() {`,
+		`    local cur="${COMP_WORDS[COMP_CWORD]}"`,
+		`    local prev="${COMP_WORDS[COMP_CWORD-1]}"`,
+		`    local path=""`,
+		`    local i word skip=0 positional=0`,
+		`    local shortvalue='^(:-[bdv]*[f])$'`,
+		`    for ((i = 1; i < COMP_CWORD; i++)); do`,
+		`        word="${COMP_WORDS[i]}"`,
+		`        if ((skip)); then`,
+		`            [[ "$word" == "=" ]] || skip=0`,
+		`            continue`,
+		`        fi`,
+		`        if [[ "${path}:${word}" =~ $shortvalue ]]; then`,
+		`            skip=1`,
+		`            continue`,
+		`        fi`,
+		`        case "${path}:${word}" in`,
+		`            ':--format'|':--style') skip=1 ;;`,
+		`            ':user') path='user' ;;`,
+		`            'user:--home') skip=1 ;;`,
+		`            *:=) skip=1 ;;`,
+		`            *:--) positional=1; break ;;`,
+		`            *:-*) ;;`,
+		`            *) positional=1; break ;;`,
+		`        esac`,
+		`    done`,
+		``,
+		`    case "$path" in`,
+		`        '')`,
+		`            case "$prev" in`,
+		`                --format|-f) COMPREPLY=($(compgen -W 'text json' -- "$cur")); return ;;`,
+		`                --style) COMPREPLY=($(compgen -W 'bright dark' -- "$cur")); return ;;`,
+		`            esac`,
+		`            if [[ "$cur" == -* ]]; then`,
+		`                COMPREPLY=($(compgen -W '--verbose -v --format -f --style -b -d' -- "$cur"))`,
+		`            elif ((!positional)); then`,
+		`                COMPREPLY=($(compgen -W 'user' -- "$cur"))`,
+		`            else`,
+		`                COMPREPLY=($(compgen -f -- "$cur"))`,
+		`            fi`,
+		`            ;;`,
+		`        'user')`,
+		`            case "$prev" in`,
+		`                --home) COMPREPLY=($(compgen -f -- "$cur")); return ;;`,
+		`            esac`,
+		`            if [[ "$cur" == -* ]]; then`,
+		`                COMPREPLY=($(compgen -W '--home' -- "$cur"))`,
+		`            else`,
+		`                COMPREPLY=($(compgen -f -- "$cur"))`,
+		`            fi`,
+		`            ;;`,
+		`    esac`,
+		`}`,
+		`complete -F _tool_completion tool`,
+	})
+}
+
+func Test_ZshCompletion(t *testing.T) {
+	parser := NewParser("tool")
+	parser.Count("verbose", "Be verbose")
+	parser.SetShortFlag('v', "verbose")
+	parser.Choices("format", []string{"text", "json"}, "Output format")
+	parser.SetShortFlag('f', "format")
+	parser.Mode("style", "bright", map[rune]string{'b': "bright", 'd': "dark"}, "Colour style")
+
+	user := parser.AddCommand("user", "User operations")
+	user.String("home", "", "Home directory")
+	user.PositionalChoices("ACTION", []string{"add", "del"}, "What to do")
+	compareCompletion(t, parser.SPrintZshCompletion("tool"), []string{
+		`#compdef tool`,
+		``,
+		`_tool() {`,
+		`    local context state state_descr line`,
+		`    typeset -A opt_args`,
+		`    _arguments -C -s \`,
+		`        '*--verbose[Be verbose]' \`,
+		`        '*-v[Be verbose]' \`,
+		`        '--format[Output format]:STRING:(text json)' \`,
+		`        '-f[Output format]:STRING:(text json)' \`,
+		`        '--style[Colour style]:STRING:(bright dark)' \`,
+		`        '-b[bright]' \`,
+		`        '-d[dark]' \`,
+		`        '1: :->command' \`,
+		`        '*:: :->args'`,
+		`    case $state in`,
+		`        command)`,
+		`            local -a commands`,
+		`            commands=(`,
+		`                'user:User operations'`,
+		`            )`,
+		`            _describe 'command' commands`,
+		`            ;;`,
+		`        args)`,
+		`            case $line[1] in`,
+		`                user) _tool_user ;;`,
+		`            esac`,
+		`            ;;`,
+		`    esac`,
+		`}`,
+		``,
+		`_tool_user() {`,
+		`    _arguments -s \`,
+		`        '--home[Home directory]:STRING:_files' \`,
+		`        '1:ACTION:(add del)'`,
+		`}`,
+		``,
+		`_tool "$@"`,
+	})
+}
+
+func Test_FishCompletion(t *testing.T) {
+	parser := NewParser("tool")
+	parser.Count("verbose", "Be verbose")
+	parser.SetShortFlag('v', "verbose")
+	parser.Choices("format", []string{"text", "json"}, "Output format")
+	parser.SetShortFlag('f', "format")
+	parser.Mode("style", "bright", map[rune]string{'b': "bright", 'd': "dark"}, "Colour style")
+
+	user := parser.AddCommand("user", "User operations")
+	user.String("home", "", "Home directory")
+	user.PositionalChoices("ACTION", []string{"add", "del"}, "What to do")
+	compareCompletion(t, parser.SPrintFishCompletion("tool"), []string{
+		`# fish completion for tool`,
+		`complete -c tool -n '__fish_use_subcommand' -f -a user -d 'User operations'`,
+		`complete -c tool -n '__fish_use_subcommand' -l verbose -s v -d 'Be verbose'`,
+		`complete -c tool -n '__fish_use_subcommand' -l format -s f -d 'Output format' -x -a 'text json'`,
+		`complete -c tool -n '__fish_use_subcommand' -l style -d 'Colour style' -x -a 'bright dark'`,
+		`complete -c tool -n '__fish_use_subcommand' -s b -d 'bright'`,
+		`complete -c tool -n '__fish_use_subcommand' -s d -d 'dark'`,
+		`complete -c tool -n '__fish_seen_subcommand_from user' -l home -d 'Home directory' -r`,
+		`complete -c tool -n '__fish_seen_subcommand_from user' -f -a 'add del' -d 'ACTION'`,
+	})
+}
+
+func Test_CompletionEscaping(t *testing.T) {
+	parser := NewParser("tool")
+	parser.String("quote", "", "It's [here]: now")
+
+	zsh := parser.SPrintZshCompletion("my-tool")
+	if !strings.Contains(zsh, `'--quote[It'\''s \[here\]\: now]:STRING:_files'`) {
+		t.Errorf("Zsh help not escaped:\n%s", zsh)
+	}
+	if !strings.Contains(zsh, `_my_tool "$@"`) {
+		t.Errorf("Zsh function name not sanitised:\n%s", zsh)
+	}
+
+	fish := parser.SPrintFishCompletion("my-tool")
+	if !strings.Contains(fish, `-d 'It'\''s [here]: now'`) {
+		t.Errorf("Fish help not escaped:\n%s", fish)
+	}
+}
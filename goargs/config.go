@@ -0,0 +1,186 @@
+package goargs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format of a configuration file
+type ConfigFormat string
+
+const (
+	// JSON object of flag names to values; arrays are accepted for Appender and Func flags
+	CONFIG_JSON ConfigFormat = "json"
+	// `name = value` lines; `#` and `;` start comment lines. Appender and Func flags can be repeated.
+	CONFIG_INI ConfigFormat = "ini"
+)
+
+/*
+Load values for already-declared flags from a configuration file.
+Files with a `.json` extension are read as CONFIG_JSON, all others as CONFIG_INI.
+See ParseConfig() for further behaviours.
+*/
+func (p *Parser) ParseConfigFile(path string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	format := CONFIG_INI
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		format = CONFIG_JSON
+	}
+
+	if err := p.ParseConfig(fh, format); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+/*
+Load values for already-declared flags from configuration data. Keys are flag long names.
+
+Must be called before Parse(), which assigns the loaded values to the flags that are neither
+specified on the command line, nor set from an environment variable.
+Precedence is: default value < configuration < environment variable < command line token
+
+Loading several configurations in turn lets later ones override keys from earlier ones.
+
+* If flag definitions are required (default), returns an error for unknown keys
+* Else, unknown keys are ignored
+*/
+func (p *Parser) ParseConfig(reader io.Reader, format ConfigFormat) error {
+	var values map[string][]string
+	var err error
+
+	switch format {
+	case CONFIG_JSON:
+		values, err = readJsonConfig(reader)
+	case CONFIG_INI:
+		values, err = readIniConfig(reader)
+	default:
+		return fmt.Errorf("unknown configuration format '%s'", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	// check all keys, in a stable order, before storing any of them
+	known := map[string][]string{}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		vals := values[key]
+		def, ok := p.lookupFlag(key)
+		if !ok {
+			if p.require_flagdefs {
+				return fmt.Errorf("configuration: %w", &UnknownFlagError{key, false, p.longSuggestions(key)})
+			}
+			continue
+		}
+		switch def.(type) {
+		case def_Appender, def_Func:
+		default:
+			if len(vals) != 1 {
+				return fmt.Errorf("configuration key '%s' does not accept multiple values", key)
+			}
+		}
+		known[def.getName()] = vals
+	}
+	maps.Copy(p.config_values, known)
+	return nil
+}
+
+// assign loaded configuration values to each flag that was neither otherwise set nor overridden
+func (p *Parser) applyConfig(seen map[string]bool, overridden map[string]bool) error {
+	for _, name := range p.longnames {
+		vals, ok := p.config_values[name]
+		if !ok || seen[name] || overridden[name] {
+			continue
+		}
+		for _, value := range vals {
+			if err := p.definitions[name].assign(value); err != nil {
+				return fmt.Errorf("from configuration: %w", p.valueError(name, value, err))
+			}
+		}
+		p.markSet(seen, name, SOURCE_CONFIG, -1)
+	}
+	return nil
+}
+
+func readJsonConfig(reader io.Reader) (map[string][]string, error) {
+	var data map[string]interface{}
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]string)
+	for key, item := range data {
+		if items, ok := item.([]interface{}); ok {
+			values[key] = []string{}
+			for _, elem := range items {
+				val, err := jsonScalar(key, elem)
+				if err != nil {
+					return nil, err
+				}
+				values[key] = append(values[key], val)
+			}
+		} else {
+			val, err := jsonScalar(key, item)
+			if err != nil {
+				return nil, err
+			}
+			values[key] = []string{val}
+		}
+	}
+	return values, nil
+}
+
+func jsonScalar(key string, item interface{}) (string, error) {
+	switch val := item.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	default:
+		return "", fmt.Errorf("unsupported value for configuration key '%s': %v", key, item)
+	}
+}
+
+func readIniConfig(reader io.Reader) (map[string][]string, error) {
+	values := make(map[string][]string)
+	scanner := bufio.NewScanner(reader)
+
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			return nil, fmt.Errorf("line %d: sections are not supported", lineno)
+		}
+
+		key, val, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected 'name = value'", lineno)
+		}
+		key = strings.TrimSpace(key)
+		val = strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		}
+		values[key] = append(values[key], val)
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,140 @@
+package goargs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_ConfigJson(t *testing.T) {
+	parser := NewParser("")
+	name := parser.String("name", "nobody", "help")
+	age := parser.Int("age", -1, "help")
+	admit := parser.Bool("admit", false, "help")
+	wait := parser.Duration("wait", time.Second, "help")
+	dish := parser.Choices("dish", []string{"rice", "noodles"}, "help")
+	mode := parser.Mode("style", "chinese", map[rune]string{'c': "chinese", 'j': "japanese"}, "help")
+	toppings := parser.Appender("topping", "help")
+
+	config := `{
+		"name": "Alex",
+		"age": 20,
+		"admit": true,
+		"wait": "3s",
+		"dish": "noodles",
+		"style": "japanese",
+		"topping": ["egg", "bamboo"]
+	}`
+	if err := parser.ParseConfig(strings.NewReader(config), CONFIG_JSON); err != nil {
+		t.Errorf("Failed config parse: %v", err)
+		return
+	}
+	if err := parser.Parse([]string{"--age", "30", "--topping", "spice"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, "Alex", *name)
+	gocheck.Equal(t, 30, *age)
+	gocheck.Equal(t, true, *admit)
+	gocheck.Equal(t, 3*time.Second, *wait)
+	gocheck.Equal(t, "noodles", *dish)
+	gocheck.Equal(t, "japanese", *mode)
+	gocheck.EqualArr(t, []string{"spice"}, *toppings)
+}
+
+func Test_ConfigIni(t *testing.T) {
+	parser := NewParser("")
+	name := parser.String("name", "nobody", "help")
+	verbose := parser.Count("verbose", "help")
+	files := parser.Appender("file", "help")
+
+	config := strings.Join([]string{
+		"# comment",
+		"; comment",
+		"",
+		`name = "Sam Smith"`,
+		"verbose = 2",
+		"file = one.txt",
+		"file = two.txt",
+	}, "\n")
+	if err := parser.ParseConfig(strings.NewReader(config), CONFIG_INI); err != nil {
+		t.Errorf("Failed config parse: %v", err)
+		return
+	}
+	if err := parser.Parse([]string{}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, "Sam Smith", *name)
+	gocheck.Equal(t, 2, *verbose)
+	gocheck.EqualArr(t, []string{"one.txt", "two.txt"}, *files)
+}
+
+func Test_ConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(path, []byte(`{"port": 8080}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewParser("")
+	port := parser.Int("port", 80, "help")
+
+	if err := parser.ParseConfigFile(path); err != nil {
+		t.Errorf("Failed config parse: %v", err)
+		return
+	}
+	if err := parser.Parse([]string{}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+	gocheck.Equal(t, 8080, *port)
+}
+
+func Test_ConfigFail(t *testing.T) {
+	parser := NewParser("")
+	dish := parser.Choices("dish", []string{"rice", "noodles"}, "help")
+
+	if err := parser.ParseConfig(strings.NewReader("unknown = 1"), CONFIG_INI); err == nil {
+		t.Errorf("Unknown key should have failed")
+	}
+	if err := parser.ParseConfig(strings.NewReader(`{"dish": ["rice", "noodles"]}`), CONFIG_JSON); err == nil {
+		t.Errorf("Array for a non-appender should have failed")
+	}
+	if err := parser.ParseConfig(strings.NewReader("[section]"), CONFIG_INI); err == nil {
+		t.Errorf("Section should have failed")
+	}
+
+	if err := parser.ParseConfig(strings.NewReader("dish = bread"), CONFIG_INI); err != nil {
+		t.Errorf("Failed config parse: %v", err)
+		return
+	}
+	if err := parser.Parse([]string{}); err == nil {
+		t.Errorf("Invalid choice from configuration should have failed, got '%s'", *dish)
+	}
+
+	parser.RequireFlagDefs(false)
+	if err := parser.ParseConfig(strings.NewReader("unknown = 1"), CONFIG_INI); err != nil {
+		t.Errorf("Unknown key should have been ignored: %v", err)
+	}
+}
+
+func Test_ConfigFail_Atomic(t *testing.T) {
+	parser := NewParser("")
+	name := parser.String("name", "nobody", "help")
+
+	// a failed configuration stores none of its values
+	if err := parser.ParseConfig(strings.NewReader("name = alice\nunknown = 1\nzzz = 2"), CONFIG_INI); err == nil {
+		t.Errorf("Unknown key should have failed")
+	}
+	if err := parser.Parse([]string{}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "nobody", *name)
+}
@@ -0,0 +1,133 @@
+package goargs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type t_ConstraintKind int
+
+const (
+	_CONSTRAINT_AT_MOST_ONE t_ConstraintKind = iota
+	_CONSTRAINT_EXACTLY_ONE
+	_CONSTRAINT_REQUIRES
+)
+
+// A constraint across flags. For _CONSTRAINT_REQUIRES, the first name requires the others.
+type t_Constraint struct {
+	kind  t_ConstraintKind
+	names []string
+}
+
+func (p *Parser) addConstraint(kind t_ConstraintKind, names []string) {
+	for _, name := range names {
+		if _, ok := p.definitions[name]; !ok {
+			panic(fmt.Sprintf("Flag '--%s' not yet defined", name))
+		}
+	}
+	p.constraints = append(p.constraints, t_Constraint{kind, names})
+}
+
+/*
+Allow at most one of the named flags to be supplied.
+Parse() returns a *ConflictingFlagsError naming the supplied flags otherwise.
+A flag supplied on the command line overrides the others' environment and configuration values.
+Panics if a long flag is not yet registered.
+*/
+func (p *Parser) SetMutuallyExclusive(longnames ...string) {
+	p.addConstraint(_CONSTRAINT_AT_MOST_ONE, longnames)
+}
+
+/*
+Require exactly one of the named flags to be supplied.
+Parse() returns a *MissingOneOfError if none was supplied, or a *ConflictingFlagsError naming the supplied flags.
+A flag supplied on the command line overrides the others' environment and configuration values.
+Panics if a long flag is not yet registered.
+*/
+func (p *Parser) SetExactlyOne(longnames ...string) {
+	p.addConstraint(_CONSTRAINT_EXACTLY_ONE, longnames)
+}
+
+/*
+Require the `others` flags to be supplied whenever the `longname` flag is, e.g. `--cert` requires `--key`.
+Parse() returns a *DependentFlagError naming the missing flags otherwise.
+Panics if a long flag is not yet registered.
+*/
+func (p *Parser) SetRequires(longname string, others ...string) {
+	p.addConstraint(_CONSTRAINT_REQUIRES, append([]string{longname}, others...))
+}
+
+/*
+The other flags of an "at most one" or "exactly one" constraint that a flag supplied on the command line overrides.
+They then take no environment or configuration value, keeping the precedence
+default < configuration < environment < command line across the constrained flags.
+*/
+func (p *Parser) overriddenByCLI(seen map[string]bool) map[string]bool {
+	overridden := map[string]bool{}
+	for _, constraint := range p.constraints {
+		if constraint.kind == _CONSTRAINT_REQUIRES {
+			continue
+		}
+		if !slices.ContainsFunc(constraint.names, func(name string) bool { return seen[name] }) {
+			continue
+		}
+		for _, name := range constraint.names {
+			if !seen[name] {
+				overridden[name] = true
+			}
+		}
+	}
+	return overridden
+}
+
+// check the constraints across the flags that were seen, in the order they were declared
+func (p *Parser) checkConstraints(seen map[string]bool) error {
+	for _, constraint := range p.constraints {
+		given := []string{}
+		missing := []string{}
+		for _, name := range constraint.names {
+			if seen[name] {
+				given = append(given, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+
+		switch constraint.kind {
+		case _CONSTRAINT_AT_MOST_ONE, _CONSTRAINT_EXACTLY_ONE:
+			if len(given) > 1 {
+				return &ConflictingFlagsError{given}
+			}
+			if len(given) == 0 && constraint.kind == _CONSTRAINT_EXACTLY_ONE {
+				return &MissingOneOfError{constraint.names}
+			}
+		case _CONSTRAINT_REQUIRES:
+			if seen[constraint.names[0]] && len(missing) > 0 {
+				return &DependentFlagError{constraint.names[0], missing}
+			}
+		}
+	}
+	return nil
+}
+
+// the help lines summarising the constraints, if any
+func (p *Parser) constraintHelpLines() []string {
+	if len(p.constraints) == 0 {
+		return []string{}
+	}
+
+	helplines := []string{"", "Constraints:"}
+	for _, constraint := range p.constraints {
+		flags := "--" + strings.Join(constraint.names, ", --")
+		switch constraint.kind {
+		case _CONSTRAINT_AT_MOST_ONE:
+			helplines = append(helplines, fmt.Sprintf("  at most one of %s", flags))
+		case _CONSTRAINT_EXACTLY_ONE:
+			helplines = append(helplines, fmt.Sprintf("  exactly one of %s", flags))
+		case _CONSTRAINT_REQUIRES:
+			helplines = append(helplines, fmt.Sprintf("  --%s requires --%s", constraint.names[0], strings.Join(constraint.names[1:], ", --")))
+		}
+	}
+	return helplines
+}
@@ -0,0 +1,125 @@
+package goargs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Constraints(t *testing.T) {
+	parser := NewParser("fetch")
+	parser.Bool("json", false, "JSON output")
+	parser.Bool("yaml", false, "YAML output")
+	parser.Bool("table", false, "Table output")
+	parser.String("file", "", "Local source")
+	parser.String("url", "", "Remote source")
+	parser.String("cert", "", "Client certificate")
+	parser.String("key", "", "Client key")
+	parser.SetMutuallyExclusive("json", "yaml", "table")
+	parser.SetExactlyOne("file", "url")
+	parser.SetRequires("cert", "key")
+
+	if err := parser.Parse([]string{"--json", "--url", "http://x", "--cert", "c", "--key", "k"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+
+	var conflict *ConflictingFlagsError
+	err := parser.Parse([]string{"--table", "--file", "f", "--json"})
+	if !errors.As(err, &conflict) {
+		t.Errorf("Expected ConflictingFlagsError, got: %v", err)
+	} else {
+		gocheck.EqualArr(t, []string{"json", "table"}, conflict.Flags)
+		gocheck.Equal(t, "flags cannot be used together: --json, --table", err.Error())
+	}
+
+	err = parser.Parse([]string{"--file", "f", "--url", "u"})
+	if !errors.As(err, &conflict) {
+		t.Errorf("Expected ConflictingFlagsError, got: %v", err)
+	} else {
+		gocheck.EqualArr(t, []string{"file", "url"}, conflict.Flags)
+	}
+
+	var oneof *MissingOneOfError
+	err = parser.Parse([]string{"--yaml"})
+	if !errors.As(err, &oneof) {
+		t.Errorf("Expected MissingOneOfError, got: %v", err)
+	} else {
+		gocheck.EqualArr(t, []string{"file", "url"}, oneof.Flags)
+		gocheck.Equal(t, "one of these flags is required: --file, --url", err.Error())
+	}
+
+	var dependent *DependentFlagError
+	err = parser.Parse([]string{"--file", "f", "--cert", "c"})
+	if !errors.As(err, &dependent) {
+		t.Errorf("Expected DependentFlagError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "cert", dependent.Flag)
+		gocheck.EqualArr(t, []string{"key"}, dependent.Missing)
+		gocheck.Equal(t, "--cert requires --key", err.Error())
+	}
+
+	// the key alone is fine
+	if err := parser.Parse([]string{"--file", "f", "--key", "k"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+}
+
+func Test_Constraints_Env(t *testing.T) {
+	parser := NewParser("fetch")
+	json := parser.Bool("json", false, "JSON output")
+	parser.Bool("yaml", false, "YAML output")
+	parser.String("file", "", "Local source")
+	url := parser.String("url", "", "Remote source")
+	parser.SetMutuallyExclusive("json", "yaml")
+	parser.SetExactlyOne("file", "url")
+	parser.SetEnvVar("json", "FETCH_JSON")
+	parser.SetEnvVar("url", "FETCH_URL")
+	t.Setenv("FETCH_JSON", "true")
+	t.Setenv("FETCH_URL", "http://x")
+
+	// the command line overrides the environment
+	if err := parser.Parse([]string{"--yaml", "--file", "f"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, false, *json)
+	gocheck.Equal(t, "", *url)
+	gocheck.Equal(t, false, parser.Changed("json"))
+	gocheck.Equal(t, false, parser.Changed("url"))
+
+	// the environment satisfies the constraints
+	if err := parser.Parse([]string{}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, true, *json)
+	gocheck.Equal(t, "http://x", *url)
+
+	// flags given together on the command line still conflict
+	var conflict *ConflictingFlagsError
+	if err := parser.Parse([]string{"--file", "f", "--url", "u"}); !errors.As(err, &conflict) {
+		t.Errorf("Expected ConflictingFlagsError, got: %v", err)
+	}
+}
+
+func Test_Constraints_Help(t *testing.T) {
+	parser := NewParser("fetch")
+	parser.Bool("json", false, "JSON output")
+	parser.Bool("yaml", false, "YAML output")
+	parser.Bool("table", false, "Table output")
+	parser.String("file", "", "Local source")
+	parser.String("url", "", "Remote source")
+	parser.String("cert", "", "Client certificate")
+	parser.String("key", "", "Client key")
+	parser.SetMutuallyExclusive("json", "yaml", "table")
+	parser.SetExactlyOne("file", "url")
+	parser.SetRequires("cert", "key")
+	lines := strings.Split(parser.SPrintHelp(), "\n")
+	gocheck.EqualArr(t, []string{
+		"",
+		"Constraints:",
+		"  at most one of --json, --yaml, --table",
+		"  exactly one of --file, --url",
+		"  --cert requires --key",
+	}, lines[len(lines)-5:])
+}
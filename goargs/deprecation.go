@@ -0,0 +1,137 @@
+package goargs
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// How a deprecated flag is reported, and the flag its values are forwarded to, if any
+type t_Deprecation struct {
+	message     string
+	replacement string
+}
+
+/*
+Hide existing flags from help, documentation and completion. Hidden flags are still parsed as usual.
+Panics if a long flag is not yet registered.
+*/
+func (p *Parser) SetHidden(names ...string) {
+	for _, name := range names {
+		if _, ok := p.definitions[name]; !ok {
+			panic(fmt.Sprintf("Flag '--%s' not yet defined", name))
+		}
+		p.hidden[name] = true
+	}
+}
+
+/*
+Mark an existing flag as deprecated. Deprecated flags are hidden like with SetHidden().
+
+When a deprecated flag is used on the command line, Parse() records a warning with the message,
+see Warnings(). If `replacement` is not empty, the flag's value is assigned to the replacement flag instead.
+
+Panics if either flag is not yet registered, if only one of them takes a value,
+or if a deprecated Mode flag has a mode value that a Choices or Mode replacement does not accept.
+*/
+func (p *Parser) SetDeprecated(name string, message string, replacement string) {
+	def, ok := p.definitions[name]
+	if !ok {
+		panic(fmt.Sprintf("Flag '--%s' not yet defined", name))
+	}
+	if replacement != "" {
+		rdef, ok := p.definitions[replacement]
+		if !ok {
+			panic(fmt.Sprintf("Flag '--%s' not yet defined", replacement))
+		}
+		if takesValue(def) != takesValue(rdef) {
+			panic(fmt.Sprintf("Flag '--%s' cannot replace '--%s': only one of them takes a value", replacement, name))
+		}
+		// mode values are forwarded as-is, so a replacement with fixed values must accept each of them
+		if _, ismode := def.(def_Mode); ismode && fixedValues(rdef) != nil {
+			for _, value := range fixedValues(def) {
+				if !slices.Contains(fixedValues(rdef), value) {
+					panic(fmt.Sprintf("Flag '--%s' cannot replace '--%s': it does not accept the mode '%s'", replacement, name, value))
+				}
+			}
+		}
+	}
+	p.hidden[name] = true
+	p.deprecations[name] = t_Deprecation{message, replacement}
+}
+
+/*
+Also write warnings to `out` as they are recorded during Parse(), one per line.
+e.g. `parser.SetWarningOutput(os.Stderr)`. A `nil` writer (the default) writes nothing.
+Sub-commands added afterwards inherit the writer.
+*/
+func (p *Parser) SetWarningOutput(out io.Writer) {
+	p.warning_output = out
+}
+
+// The warnings recorded during the last Parse(), such as uses of deprecated flags
+func (p *Parser) Warnings() []string {
+	return slices.Clone(p.warnings)
+}
+
+func (p *Parser) warn(message string) {
+	if slices.Contains(p.warnings, message) {
+		return
+	}
+	p.warnings = append(p.warnings, message)
+	if p.warning_output != nil {
+		fmt.Fprintln(p.warning_output, message)
+	}
+}
+
+// record a warning if a definition is deprecated, and return the definition its value goes to
+func (p *Parser) deprecationTarget(def t_VarDef) t_VarDef {
+	deprecation, ok := p.deprecations[def.getName()]
+	if !ok {
+		return def
+	}
+
+	message := fmt.Sprintf("Flag --%s is deprecated", def.getName())
+	if deprecation.message != "" {
+		message += ": " + deprecation.message
+	}
+	if deprecation.replacement == "" {
+		p.warn(message)
+		return def
+	}
+	p.warn(fmt.Sprintf("%s (use --%s instead)", message, deprecation.replacement))
+	return p.definitions[deprecation.replacement]
+}
+
+// activate a switch-like short flag, or its replacement if deprecated, and return the name of the flag activated
+func (p *Parser) activateShortFlag(sf t_ShortFlag) (string, error) {
+	target := p.deprecationTarget(sf.def)
+	mode, ismode := sf.def.(def_Mode)
+	if ismode && target.getName() != mode.name {
+		value := mode.modes[sf.flag]
+		if err := target.assign(value); err != nil {
+			return "", p.valueError(target.getName(), value, err)
+		}
+		return target.getName(), nil
+	}
+	// a Mode short flag selects its own mode, other switches take the implicit value like their long flag
+	if implicit, ok := p.implicit_values[target.getName()]; ok && !ismode {
+		if err := target.assign(implicit); err != nil {
+			return "", p.valueError(target.getName(), implicit, err)
+		}
+		return target.getName(), nil
+	}
+	t_ShortFlag{sf.flag, target}.activate(p.negatable_bools)
+	return target.getName(), nil
+}
+
+// the long names of the flags to list in help, documentation and completion, in declaration order
+func (p *Parser) listedNames() []string {
+	names := []string{}
+	for _, name := range p.longnames {
+		if !p.hidden[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
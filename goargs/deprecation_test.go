@@ -0,0 +1,94 @@
+package goargs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Hidden(t *testing.T) {
+	parser := NewParser("tool")
+	name := parser.String("name", "bob", "Who")
+	secret := parser.String("secret", "", "Shh")
+	parser.SetShortFlag('s', "secret")
+	parser.SetHidden("secret")
+
+	helptext := parser.SPrintHelp()
+	gocheck.Equal(t, false, strings.Contains(helptext, "secret"))
+	gocheck.EqualArr(t, []string{"--name"}, parser.Complete([]string{"-"}))
+
+	if err := parser.Parse([]string{"--name", "alice", "-s", "xyzzy"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "alice", *name)
+	gocheck.Equal(t, "xyzzy", *secret)
+}
+
+func Test_Deprecated(t *testing.T) {
+	parser := NewParser("tool")
+	colour := parser.String("colour", "auto", "Colour output")
+	parser.String("color", "auto", "Color output")
+	parser.SetDeprecated("colour", "", "color")
+	old := parser.Bool("old", false, "Old behaviour")
+	parser.SetDeprecated("old", "it will be removed in 2.0", "")
+	color := parser.Choices("color-mode", []string{"auto", "always", "never"}, "Colouring")
+	parser.Mode("paint", "none", map[rune]string{'r': "red", 'g': "green"}, "Paint")
+	parser.Mode("paint-old", "none", map[rune]string{'R': "red", 'G': "green"}, "Paint")
+	parser.SetDeprecated("paint-old", "", "paint")
+	var out bytes.Buffer
+	parser.SetWarningOutput(&out)
+
+	gocheck.Equal(t, false, strings.Contains(parser.SPrintHelp(), "colour"))
+
+	if err := parser.Parse([]string{"--colour", "never", "--old", "--colour=always", "-G"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "auto", *colour)
+	gocheck.Equal(t, "always", *parser.definitions["color"].(def_String).value)
+	gocheck.Equal(t, true, *old)
+	gocheck.Equal(t, "auto", *color)
+	gocheck.Equal(t, "green", *parser.definitions["paint"].(def_Mode).value)
+
+	expect := []string{
+		"Flag --colour is deprecated (use --color instead)",
+		"Flag --old is deprecated: it will be removed in 2.0",
+		"Flag --paint-old is deprecated (use --paint instead)",
+	}
+	gocheck.EqualArr(t, expect, parser.Warnings())
+	gocheck.Equal(t, strings.Join(expect, "\n")+"\n", out.String())
+
+	if err := parser.Parse([]string{"--color", "never"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, 0, len(parser.Warnings()))
+}
+
+func Test_Deprecated_Mismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Replacing a switch with a value flag should have panicked")
+		}
+	}()
+	parser := NewParser("tool")
+	parser.Bool("quiet", false, "Shh")
+	parser.String("level", "info", "Log level")
+	parser.SetDeprecated("quiet", "", "level")
+}
+
+func Test_Deprecated_ModeChoices(t *testing.T) {
+	parser := NewParser("tool")
+	parser.Mode("paint", "none", map[rune]string{'r': "red", 'g': "green"}, "Paint")
+	parser.Choices("colour", []string{"none", "red", "green"}, "Colour")
+	parser.SetDeprecated("paint", "", "colour")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Replacing a mode with a choice it does not accept should have panicked")
+		}
+	}()
+	parser.Mode("style", "plain", map[rune]string{'b': "bold", 'i': "italic"}, "Style")
+	parser.Choices("font", []string{"plain", "bold"}, "Font")
+	parser.SetDeprecated("style", "", "font")
+}
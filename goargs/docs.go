@@ -0,0 +1,340 @@
+package goargs
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// The documentation of a single flag, shared by the documentation exporters
+type t_FlagDoc struct {
+	anchor   string
+	flag     string
+	shorts   []string
+	typename string
+	defval   string
+	choices  []string
+	help     string
+	notes    []string
+}
+
+// The documentation of a parser, or of one of its sub-commands
+type t_CommandDoc struct {
+	path        []string
+	anchor      string
+	helptext    string
+	flags       []t_FlagDoc
+	positionals []t_FlagDoc
+	commands    []string
+	summaries   []string
+	posttext    string
+}
+
+/*
+The anchor identifying a flag in exported documentation, from the sub-command path of its parser.
+e.g. `FlagAnchor([]string{"db", "migrate"}, "dry-run")` is "flag-db.migrate.dry-run"
+The path is joined with `.`, which flag and command names cannot contain, so that anchors are unique.
+*/
+func FlagAnchor(commandpath []string, name string) string {
+	return "flag-" + strings.Join(append(slices.Clone(commandpath), name), ".")
+}
+
+// The anchor identifying a positional in exported documentation, e.g. "arg-db.migrate.TARGET"
+func positionalAnchor(commandpath []string, name string) string {
+	return "arg-" + strings.Join(append(slices.Clone(commandpath), name), ".")
+}
+
+// The anchor identifying a sub-command section in exported documentation, e.g. "command-db.migrate"
+func commandAnchor(commandpath []string) string {
+	if len(commandpath) == 0 {
+		return "command"
+	}
+	return "command-" + strings.Join(commandpath, ".")
+}
+
+// collect the documentation of a parser
+func (p *Parser) commandDoc(path []string) t_CommandDoc {
+	doc := t_CommandDoc{
+		path:     path,
+		anchor:   commandAnchor(path),
+		helptext: p.helptext,
+		posttext: p.post_helptext,
+	}
+
+	for _, name := range p.listedNames() {
+		def := p.definitions[name]
+		fdoc := t_FlagDoc{anchor: FlagAnchor(path, name), flag: p.longFlagsLabel(name), help: def.getHelpString()}
+
+		if mode, ismode := def.(def_Mode); ismode {
+			fdoc.help = helpSummary(def)
+			for _, short := range p.shortFlagsFor(name) {
+				fdoc.choices = append(fdoc.choices, fmt.Sprintf("-%c: %s", short, mode.modes[short]))
+			}
+		} else {
+			for _, short := range p.shortFlagsFor(name) {
+				fdoc.shorts = append(fdoc.shorts, fmt.Sprintf("-%c", short))
+			}
+			fdoc.choices = fixedValues(def)
+		}
+		if takesValue(def) {
+			fdoc.typename = valueTypeName(def)
+		}
+		fdoc.defval, _ = defaultString(def)
+
+		if p.required[name] {
+			fdoc.notes = append(fdoc.notes, "Required.")
+		}
+		switch def.(type) {
+		case def_Count:
+			fdoc.notes = append(fdoc.notes, "Each appearance is counted.")
+		case def_Appender:
+			fdoc.notes = append(fdoc.notes, "Can be specified multiple times.")
+		}
+		if implicit, ok := p.implicit_values[name]; ok {
+			fdoc.notes = append(fdoc.notes, fmt.Sprintf("Without value: %s", implicit))
+		}
+		if envname := p.envName(name); envname != "" {
+			fdoc.notes = append(fdoc.notes, fmt.Sprintf("Environment: %s", envname))
+		}
+		doc.flags = append(doc.flags, fdoc)
+	}
+
+	for _, pdef := range p.positional_defs {
+		name := pdef.def.getName()
+		fdoc := t_FlagDoc{anchor: positionalAnchor(path, name), flag: name, help: pdef.def.getHelpString()}
+		if pdef.variadic {
+			fdoc.flag += "..."
+		}
+		fdoc.typename = valueTypeName(pdef.def)
+		fdoc.choices = fixedValues(pdef.def)
+		if pdef.optional {
+			fdoc.notes = append(fdoc.notes, "Optional.")
+		}
+		doc.positionals = append(doc.positionals, fdoc)
+	}
+
+	for _, verb := range p.commandnames {
+		doc.commands = append(doc.commands, verb)
+		doc.summaries = append(doc.summaries, strings.SplitN(p.commands[verb].helptext, "\n", 2)[0])
+	}
+	return doc
+}
+
+// collect the documentation of a parser and of all its nested sub-commands, depth first
+func (p *Parser) commandDocs() []t_CommandDoc {
+	docs := []t_CommandDoc{}
+	p.walkCommands(nil, func(path []string, cp *Parser) {
+		docs = append(docs, cp.commandDoc(path))
+	})
+	return docs
+}
+
+// escape text for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", "<br>")
+}
+
+// escape prose for a Markdown table cell, so that it is not taken for inline HTML
+func markdownText(text string) string {
+	return markdownCell(strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text))
+}
+
+// a Markdown inline code span, or the empty string
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + markdownCell(text) + "`"
+}
+
+/*
+Produce a Markdown reference page for the program `progname` and all its sub-commands, and return it.
+
+Each parser gets a section with its help text, and tables of its flags and positionals.
+Each flag row carries an anchor, see FlagAnchor(). Positional rows are anchored as `arg-SUB.COMMANDS.NAME`,
+and sections as `command-SUB.COMMANDS`.
+*/
+func (p *Parser) SPrintMarkdown(progname string) string {
+	lines := []string{}
+
+	for _, doc := range p.commandDocs() {
+		title := strings.Join(append([]string{progname}, doc.path...), " ")
+		level := min(len(doc.path)+1, 6)
+		lines = append(lines, fmt.Sprintf(`<a id="%s"></a>`, doc.anchor), "", fmt.Sprintf("%s %s", strings.Repeat("#", level), title), "")
+		if doc.helptext != "" {
+			lines = append(lines, "```", doc.helptext, "```", "")
+		}
+
+		if len(doc.flags) > 0 {
+			lines = append(lines,
+				"| Flag | Short | Type | Default | Choices | Description |",
+				"| --- | --- | --- | --- | --- | --- |",
+			)
+			for _, fdoc := range doc.flags {
+				shorts := []string{}
+				for _, short := range fdoc.shorts {
+					shorts = append(shorts, markdownCode(short))
+				}
+				choices := []string{}
+				for _, choice := range fdoc.choices {
+					choices = append(choices, markdownCode(choice))
+				}
+				lines = append(lines, fmt.Sprintf(`| <a id="%s"></a>%s | %s | %s | %s | %s | %s |`,
+					fdoc.anchor,
+					markdownCode(fdoc.flag),
+					strings.Join(shorts, ", "),
+					markdownText(fdoc.typename),
+					markdownCode(fdoc.defval),
+					strings.Join(choices, ", "),
+					markdownText(strings.Join(append([]string{fdoc.help}, fdoc.notes...), "\n")),
+				))
+			}
+			lines = append(lines, "")
+		}
+
+		if len(doc.positionals) > 0 {
+			lines = append(lines,
+				"| Argument | Type | Choices | Description |",
+				"| --- | --- | --- | --- |",
+			)
+			for _, fdoc := range doc.positionals {
+				choices := []string{}
+				for _, choice := range fdoc.choices {
+					choices = append(choices, markdownCode(choice))
+				}
+				lines = append(lines, fmt.Sprintf(`| <a id="%s"></a>%s | %s | %s | %s |`,
+					fdoc.anchor,
+					markdownCode(fdoc.flag),
+					markdownText(fdoc.typename),
+					strings.Join(choices, ", "),
+					markdownText(strings.Join(append([]string{fdoc.help}, fdoc.notes...), "\n")),
+				))
+			}
+			lines = append(lines, "")
+		}
+
+		if len(doc.commands) > 0 {
+			lines = append(lines, "Commands:", "")
+			for i, verb := range doc.commands {
+				entry := fmt.Sprintf("* [%s](#%s)", verb, commandAnchor(append(doc.path, verb)))
+				if doc.summaries[i] != "" {
+					entry += " - " + doc.summaries[i]
+				}
+				lines = append(lines, entry)
+			}
+			lines = append(lines, "")
+		}
+
+		if doc.posttext != "" {
+			lines = append(lines, doc.posttext, "")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// HTML inline code, or the empty string
+func htmlCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "<code>" + html.EscapeString(text) + "</code>"
+}
+
+// HTML text with line breaks
+func htmlText(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+/*
+Produce a standalone HTML reference page for the program `progname` and all its sub-commands, and return it.
+The page has the same content and anchors as SPrintMarkdown().
+*/
+func (p *Parser) SPrintHTML(progname string) string {
+	lines := []string{
+		"<!DOCTYPE html>",
+		"<html>",
+		"<head>",
+		`<meta charset="utf-8">`,
+		fmt.Sprintf("<title>%s</title>", html.EscapeString(progname)),
+		"</head>",
+		"<body>",
+	}
+
+	for _, doc := range p.commandDocs() {
+		title := strings.Join(append([]string{progname}, doc.path...), " ")
+		level := min(len(doc.path)+1, 6)
+		lines = append(lines, fmt.Sprintf(`<h%d id="%s">%s</h%d>`, level, doc.anchor, html.EscapeString(title), level))
+		if doc.helptext != "" {
+			lines = append(lines, fmt.Sprintf("<pre>%s</pre>", html.EscapeString(doc.helptext)))
+		}
+
+		if len(doc.flags) > 0 {
+			lines = append(lines,
+				"<table>",
+				"<tr><th>Flag</th><th>Short</th><th>Type</th><th>Default</th><th>Choices</th><th>Description</th></tr>",
+			)
+			for _, fdoc := range doc.flags {
+				shorts := []string{}
+				for _, short := range fdoc.shorts {
+					shorts = append(shorts, htmlCode(short))
+				}
+				choices := []string{}
+				for _, choice := range fdoc.choices {
+					choices = append(choices, htmlCode(choice))
+				}
+				lines = append(lines, fmt.Sprintf(`<tr id="%s"><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+					fdoc.anchor,
+					htmlCode(fdoc.flag),
+					strings.Join(shorts, ", "),
+					html.EscapeString(fdoc.typename),
+					htmlCode(fdoc.defval),
+					strings.Join(choices, ", "),
+					htmlText(strings.Join(append([]string{fdoc.help}, fdoc.notes...), "\n")),
+				))
+			}
+			lines = append(lines, "</table>")
+		}
+
+		if len(doc.positionals) > 0 {
+			lines = append(lines,
+				"<table>",
+				"<tr><th>Argument</th><th>Type</th><th>Choices</th><th>Description</th></tr>",
+			)
+			for _, fdoc := range doc.positionals {
+				choices := []string{}
+				for _, choice := range fdoc.choices {
+					choices = append(choices, htmlCode(choice))
+				}
+				lines = append(lines, fmt.Sprintf(`<tr id="%s"><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+					fdoc.anchor,
+					htmlCode(fdoc.flag),
+					html.EscapeString(fdoc.typename),
+					strings.Join(choices, ", "),
+					htmlText(strings.Join(append([]string{fdoc.help}, fdoc.notes...), "\n")),
+				))
+			}
+			lines = append(lines, "</table>")
+		}
+
+		if len(doc.commands) > 0 {
+			lines = append(lines, "<p>Commands:</p>", "<ul>")
+			for i, verb := range doc.commands {
+				entry := fmt.Sprintf(`<li><a href="#%s">%s</a>`, commandAnchor(append(doc.path, verb)), html.EscapeString(verb))
+				if doc.summaries[i] != "" {
+					entry += " - " + html.EscapeString(doc.summaries[i])
+				}
+				lines = append(lines, entry+"</li>")
+			}
+			lines = append(lines, "</ul>")
+		}
+
+		if doc.posttext != "" {
+			lines = append(lines, fmt.Sprintf("<p>%s</p>", htmlText(doc.posttext)))
+		}
+	}
+
+	lines = append(lines, "</body>", "</html>")
+	return strings.Join(lines, "\n") + "\n"
+}
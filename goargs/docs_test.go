@@ -0,0 +1,150 @@
+package goargs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_FlagAnchor(t *testing.T) {
+	gocheck.Equal(t, "flag-verbose", FlagAnchor(nil, "verbose"))
+	gocheck.Equal(t, "flag-db.migrate.dry-run", FlagAnchor([]string{"db", "migrate"}, "dry-run"))
+}
+
+func Test_Anchors_Unique(t *testing.T) {
+	parser := NewParser("tool")
+	parser.String("db-host", "", "Root database host")
+	db := parser.AddCommand("db", "")
+	db.String("host", "", "Database host")
+	db.String("TABLE", "", "Flag named like the positional")
+	db.PositionalString("TABLE", "Table to use")
+
+	for _, page := range []string{parser.SPrintMarkdown("tool"), parser.SPrintHTML("tool")} {
+		for _, anchor := range []string{`"flag-db-host"`, `"flag-db.host"`, `"flag-db.TABLE"`, `"arg-db.TABLE"`} {
+			gocheck.Equal(t, 1, strings.Count(page, anchor))
+		}
+	}
+}
+
+func Test_Markdown(t *testing.T) {
+	parser := NewParser("tool [OPTIONS] COMMAND")
+	parser.String("name", "bob", "Who | what")
+	parser.SetShortFlag('n', "name")
+	parser.SetRequired("name")
+	parser.Mode("damage", "blunt", map[rune]string{'b': "blunt", 's': "sharp"}, "Damage type")
+	parser.Count("verbose", "Verbosity")
+
+	db := parser.AddCommand("db", "Database operations\nMore text")
+	migrate := db.AddCommand("migrate", "Run migrations")
+	migrate.Bool("dry-run", false, "Only <show> changes")
+	migrate.SetEnvVar("dry-run", "TOOL_DRY_RUN")
+	migrate.PositionalChoices("TARGET", []string{"up", "down"}, "Direction")
+	expect := strings.Join([]string{
+		`<a id="command"></a>`,
+		``,
+		`# tool`,
+		``,
+		"```",
+		`tool [OPTIONS] COMMAND`,
+		"```",
+		``,
+		`| Flag | Short | Type | Default | Choices | Description |`,
+		`| --- | --- | --- | --- | --- | --- |`,
+		"| <a id=\"flag-name\"></a>`--name` | `-n` | STRING | `bob` |  | Who \\| what<br>Required. |",
+		"| <a id=\"flag-damage\"></a>`--damage` |  | STRING | `blunt` | `-b: blunt`, `-s: sharp` | Damage type |",
+		"| <a id=\"flag-verbose\"></a>`--verbose` |  |  |  |  | Verbosity<br>Each appearance is counted. |",
+		``,
+		`Commands:`,
+		``,
+		`* [db](#command-db) - Database operations`,
+		``,
+		`<a id="command-db"></a>`,
+		``,
+		`## tool db`,
+		``,
+		"```",
+		`Database operations`,
+		`More text`,
+		"```",
+		``,
+		`Commands:`,
+		``,
+		`* [migrate](#command-db.migrate) - Run migrations`,
+		``,
+		`<a id="command-db.migrate"></a>`,
+		``,
+		`### tool db migrate`,
+		``,
+		"```",
+		`Run migrations`,
+		"```",
+		``,
+		`| Flag | Short | Type | Default | Choices | Description |`,
+		`| --- | --- | --- | --- | --- | --- |`,
+		"| <a id=\"flag-db.migrate.dry-run\"></a>`--dry-run` |  |  | `false` |  | Only &lt;show&gt; changes<br>Environment: TOOL_DRY_RUN |",
+		``,
+		`| Argument | Type | Choices | Description |`,
+		`| --- | --- | --- | --- |`,
+		"| <a id=\"arg-db.migrate.TARGET\"></a>`TARGET` | STRING | `up`, `down` | Direction |",
+		``,
+	}, "\n")
+	gocheck.EqualArr(t, strings.Split(expect, "\n"), strings.Split(parser.SPrintMarkdown("tool"), "\n"))
+}
+
+func Test_HTML(t *testing.T) {
+	parser := NewParser("tool [OPTIONS] COMMAND")
+	parser.String("name", "bob", "Who | what")
+	parser.SetShortFlag('n', "name")
+	parser.SetRequired("name")
+	parser.Mode("damage", "blunt", map[rune]string{'b': "blunt", 's': "sharp"}, "Damage type")
+	parser.Count("verbose", "Verbosity")
+
+	db := parser.AddCommand("db", "Database operations\nMore text")
+	migrate := db.AddCommand("migrate", "Run migrations")
+	migrate.Bool("dry-run", false, "Only <show> changes")
+	migrate.SetEnvVar("dry-run", "TOOL_DRY_RUN")
+	migrate.PositionalChoices("TARGET", []string{"up", "down"}, "Direction")
+	expect := strings.Join([]string{
+		`<!DOCTYPE html>`,
+		`<html>`,
+		`<head>`,
+		`<meta charset="utf-8">`,
+		`<title>tool</title>`,
+		`</head>`,
+		`<body>`,
+		`<h1 id="command">tool</h1>`,
+		`<pre>tool [OPTIONS] COMMAND</pre>`,
+		`<table>`,
+		`<tr><th>Flag</th><th>Short</th><th>Type</th><th>Default</th><th>Choices</th><th>Description</th></tr>`,
+		`<tr id="flag-name"><td><code>--name</code></td><td><code>-n</code></td><td>STRING</td><td><code>bob</code></td><td></td><td>Who | what<br>Required.</td></tr>`,
+		`<tr id="flag-damage"><td><code>--damage</code></td><td></td><td>STRING</td><td><code>blunt</code></td><td><code>-b: blunt</code>, <code>-s: sharp</code></td><td>Damage type</td></tr>`,
+		`<tr id="flag-verbose"><td><code>--verbose</code></td><td></td><td></td><td></td><td></td><td>Verbosity<br>Each appearance is counted.</td></tr>`,
+		`</table>`,
+		`<p>Commands:</p>`,
+		`<ul>`,
+		`<li><a href="#command-db">db</a> - Database operations</li>`,
+		`</ul>`,
+		`<h2 id="command-db">tool db</h2>`,
+		`<pre>Database operations`,
+		`More text</pre>`,
+		`<p>Commands:</p>`,
+		`<ul>`,
+		`<li><a href="#command-db.migrate">migrate</a> - Run migrations</li>`,
+		`</ul>`,
+		`<h3 id="command-db.migrate">tool db migrate</h3>`,
+		`<pre>Run migrations</pre>`,
+		`<table>`,
+		`<tr><th>Flag</th><th>Short</th><th>Type</th><th>Default</th><th>Choices</th><th>Description</th></tr>`,
+		`<tr id="flag-db.migrate.dry-run"><td><code>--dry-run</code></td><td></td><td></td><td><code>false</code></td><td></td><td>Only &lt;show&gt; changes<br>Environment: TOOL_DRY_RUN</td></tr>`,
+		`</table>`,
+		`<table>`,
+		`<tr><th>Argument</th><th>Type</th><th>Choices</th><th>Description</th></tr>`,
+		`<tr id="arg-db.migrate.TARGET"><td><code>TARGET</code></td><td>STRING</td><td><code>up</code>, <code>down</code></td><td>Direction</td></tr>`,
+		`</table>`,
+		`</body>`,
+		`</html>`,
+		``,
+	}, "\n")
+	gocheck.EqualArr(t, strings.Split(expect, "\n"), strings.Split(parser.SPrintHTML("tool"), "\n"))
+}
@@ -0,0 +1,184 @@
+package goargs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The hidden first token requesting completion candidates from Parse(), see Complete()
+const COMPLETE_TOKEN = "__complete"
+
+// Returned by Parse() after printing completion candidates. The program should exit successfully.
+var ErrCompletion = errors.New("completion candidates were printed")
+
+/*
+Register a function producing completion candidates for the values of a flag or of a positional,
+by name. The function receives the partial value being completed, and its candidates are then
+filtered to those starting with the partial value.
+
+Panics if no flag or positional of that name is registered.
+*/
+func (p *Parser) SetCompleter(name string, completer func(partial string) []string) {
+	if _, ok := p.definitions[name]; !ok && p.positionalDef(name) == nil {
+		panic(fmt.Sprintf("Flag or positional '%s' not yet defined", name))
+	}
+	p.completers[name] = completer
+}
+
+// Write the completion candidates printed by Parse() to `out` instead of stdout. A `nil` writer restores stdout.
+func (p *Parser) SetCompletionOutput(out io.Writer) {
+	p.completion_output = out
+}
+
+// the positional definition by name, or nil
+func (p *Parser) positionalDef(name string) t_VarDef {
+	for _, pdef := range p.positional_defs {
+		if pdef.def.getName() == name {
+			return pdef.def
+		}
+	}
+	return nil
+}
+
+// whether any of the parser's or its sub-commands' definitions has a completer
+func (p *Parser) hasCompleters() bool {
+	found := false
+	p.walkCommands(nil, func(_ []string, cp *Parser) {
+		found = found || len(cp.completers) > 0
+	})
+	return found
+}
+
+// whether any of the parser's positionals has a completer
+func (p *Parser) hasPositionalCompleters() bool {
+	for _, pdef := range p.positional_defs {
+		if _, ok := p.completers[pdef.def.getName()]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Return the completion candidates for a partial command line. `tokens` are the tokens after the
+program name, the last of which is the partial token being completed (use "" after a space).
+
+Depending on the preceding tokens, the candidates are those of:
+
+* a flag's value, when following a value-taking flag (including short flags such as `-vaN`),
+  attached to a short flag (`-vaNal`) or after `--name=`, from the flag's completer or its Choices/Mode values
+* a flag name, when the partial token starts with `-`
+* a sub-command verb, if no positional was found yet
+* a positional's value, from the positional's completer or its choices
+
+When Parse() receives COMPLETE_TOKEN as first token, it prints the candidates of the remaining
+tokens to stdout (see SetCompletionOutput()), one per line, and returns ErrCompletion.
+*/
+func (p *Parser) Complete(tokens []string) []string {
+	partial := ""
+	if len(tokens) > 0 {
+		partial = tokens[len(tokens)-1]
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	cur := p
+	npositionals := 0
+	var pending t_VarDef = nil
+
+	for _, token := range tokens {
+		if pending != nil {
+			pending = nil
+		} else if token == "--" {
+			// passdown tokens are not completed
+			return []string{}
+		} else if len(token) >= 2 && token[:2] == "--" {
+			name, _, hasvalue := strings.Cut(token[2:], "=")
+			if def, negated, _ := cur.resolveLongFlag(name); def != nil && !negated && takesValue(def) && !hasvalue {
+				if _, implicit := cur.implicit_values[def.getName()]; !implicit {
+					pending = def
+				}
+			}
+		} else if len(token) > 1 && token[:1] == "-" {
+			if _, valdef, value, known, err := cur.resolveShortFlags(token); err == nil && known && value == nil && valdef != nil {
+				if _, implicit := cur.implicit_values[valdef.getName()]; !implicit {
+					pending = valdef
+				}
+			}
+		} else if sub, ok := cur.commands[token]; ok && npositionals == 0 {
+			cur = sub
+		} else {
+			npositionals++
+		}
+	}
+
+	if pending != nil {
+		return cur.valueCandidates(pending, partial, "")
+	}
+
+	if len(partial) >= 2 && partial[:2] == "--" {
+		if name, value, hasvalue := strings.Cut(partial[2:], "="); hasvalue {
+			if def, _, _ := cur.resolveLongFlag(name); def != nil {
+				return cur.valueCandidates(def, value, "--"+name+"=")
+			}
+			return []string{}
+		}
+	} else if len(partial) > 1 && partial[:1] == "-" {
+		if _, valdef, value, known, err := cur.resolveShortFlags(partial); err == nil && known {
+			if value != nil {
+				// a value attached to a short flag, as in `-vaNal`
+				return cur.valueCandidates(valdef, *value, partial[:len(partial)-len(*value)])
+			}
+			// a complete short flag token
+			return []string{partial}
+		}
+	}
+
+	if len(partial) > 0 && partial[:1] == "-" {
+		return cur.flagCandidates(partial)
+	}
+
+	if npositionals == 0 && len(cur.commandnames) > 0 {
+		return filterPrefix(cur.commandnames, partial, "")
+	}
+
+	for i, pdef := range cur.positional_defs {
+		if i == npositionals || (pdef.variadic && i < npositionals) {
+			return cur.valueCandidates(pdef.def, partial, "")
+		}
+	}
+	return []string{}
+}
+
+// the candidate values of a definition starting with `partial`, each preceded by `prefix`
+func (p *Parser) valueCandidates(def t_VarDef, partial string, prefix string) []string {
+	if completer, ok := p.completers[def.getName()]; ok {
+		return filterPrefix(completer(partial), partial, prefix)
+	}
+	return filterPrefix(fixedValues(def), partial, prefix)
+}
+
+// the flag tokens starting with `partial`
+func (p *Parser) flagCandidates(partial string) []string {
+	flags := []string{}
+	for _, name := range p.listedNames() {
+		for _, long := range p.completionLongNames(name) {
+			flags = append(flags, "--"+long)
+		}
+		for _, short := range p.shortFlagsFor(name) {
+			flags = append(flags, fmt.Sprintf("-%c", short))
+		}
+	}
+	return filterPrefix(flags, partial, "")
+}
+
+func filterPrefix(candidates []string, partial string, prefix string) []string {
+	found := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, partial) {
+			found = append(found, prefix+candidate)
+		}
+	}
+	return found
+}
@@ -0,0 +1,95 @@
+package goargs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Complete(t *testing.T) {
+	parser := NewParser("tool")
+	parser.Count("verbose", "help")
+	parser.SetShortFlag('v', "verbose")
+	parser.Bool("all", false, "help")
+	parser.SetShortFlag('a', "all")
+	parser.String("name", "", "help")
+	parser.SetShortFlag('N', "name")
+	parser.SetCompleter("name", func(partial string) []string {
+		return []string{"alex", "sam", "alice"}
+	})
+	parser.Choices("format", []string{"text", "json"}, "help")
+
+	db := parser.AddCommand("db", "Database operations")
+	db.PositionalString("DATABASE", "help")
+	db.SetCompleter("DATABASE", func(partial string) []string {
+		return []string{"prod", "staging", partial + "-new"}
+	})
+	db.PositionalRest("TABLES", "help")
+	db.SetCompleter("TABLES", func(partial string) []string {
+		return []string{"users", "orders"}
+	})
+	parser.AddCommand("dump", "Dump everything")
+
+	gocheck.EqualArr(t, []string{"alex", "alice"}, parser.Complete([]string{"--name", "al"}))
+	gocheck.EqualArr(t, []string{"alex", "alice"}, parser.Complete([]string{"-vaN", "al"}))
+	gocheck.EqualArr(t, []string{"-vaNalex", "-vaNalice"}, parser.Complete([]string{"-vaNal"}))
+	gocheck.EqualArr(t, []string{"--name=sam"}, parser.Complete([]string{"--name=s"}))
+	gocheck.EqualArr(t, []string{"text", "json"}, parser.Complete([]string{"--format", ""}))
+	gocheck.EqualArr(t, []string{"--verbose", "-v", "--all", "-a", "--name", "-N", "--format"}, parser.Complete([]string{"-"}))
+	gocheck.EqualArr(t, []string{"--name"}, parser.Complete([]string{"--na"}))
+	gocheck.EqualArr(t, []string{"-va"}, parser.Complete([]string{"-va"}))
+	gocheck.EqualArr(t, []string{"db", "dump"}, parser.Complete([]string{"-v", "d"}))
+	gocheck.EqualArr(t, []string{"db", "dump"}, parser.Complete([]string{}))
+
+	gocheck.EqualArr(t, []string{"staging", "st-new"}, parser.Complete([]string{"db", "st"}))
+	gocheck.EqualArr(t, []string{"users", "orders"}, parser.Complete([]string{"db", "prod", ""}))
+	gocheck.EqualArr(t, []string{"orders"}, parser.Complete([]string{"db", "prod", "users", "o"}))
+	gocheck.EqualArr(t, []string{}, parser.Complete([]string{"dump", ""}))
+	gocheck.EqualArr(t, []string{}, parser.Complete([]string{"--", "-"}))
+}
+
+func Test_Complete_Parse(t *testing.T) {
+	parser := NewParser("tool")
+	parser.String("name", "", "help")
+	parser.SetCompleter("name", func(partial string) []string {
+		return []string{"alex", "sam", "alice"}
+	})
+	var out bytes.Buffer
+	parser.SetCompletionOutput(&out)
+	if err := parser.Parse([]string{COMPLETE_TOKEN, "--name", "s"}); err != ErrCompletion {
+		t.Errorf("Expected ErrCompletion, got: %v", err)
+	}
+	gocheck.Equal(t, "sam\n", out.String())
+}
+
+func Test_Complete_Scripts(t *testing.T) {
+	parser := NewParser("tool")
+	parser.String("name", "", "help")
+	parser.SetShortFlag('N', "name")
+	parser.SetCompleter("name", func(partial string) []string {
+		return []string{"alex", "sam", "alice"}
+	})
+
+	db := parser.AddCommand("db", "Database operations")
+	db.PositionalString("DATABASE", "help")
+	db.SetCompleter("DATABASE", func(partial string) []string {
+		return []string{"prod", "staging", partial + "-new"}
+	})
+
+	bash := parser.SPrintBashCompletion("tool")
+	if !strings.Contains(bash, `--name|-N) COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" __complete "${COMP_WORDS[@]:1:COMP_CWORD}")" -- "$cur")); return ;;`) {
+		t.Errorf("Bash script does not complete --name dynamically:\n%s", bash)
+	}
+
+	zsh := parser.SPrintZshCompletion("tool")
+	if !strings.Contains(zsh, `'--name[help]:STRING:{_tool__dynamic}'`) || !strings.Contains(zsh, `'1:DATABASE:{_tool__dynamic}'`) {
+		t.Errorf("Zsh script does not complete dynamically:\n%s", zsh)
+	}
+
+	fish := parser.SPrintFishCompletion("tool")
+	if !strings.Contains(fish, `-l name -s N -d 'help' -x -a '(tool __complete (commandline -opc)[2..-1] (commandline -ct))'`) {
+		t.Errorf("Fish script does not complete --name dynamically:\n%s", fish)
+	}
+}
@@ -0,0 +1,64 @@
+package goargs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*
+Bind an existing flag to an environment variable.
+If the variable is set (and not empty) and the flag is not specified on the command line,
+the variable's value is assigned to the flag during parsing.
+
+Precedence is: default value < environment variable < command line token
+
+Panics if the long flag is not yet registered.
+*/
+func (p *Parser) SetEnvVar(longname string, envname string) {
+	if _, ok := p.definitions[longname]; !ok {
+		panic(fmt.Sprintf("Flag '--%s' not yet defined", longname))
+	}
+	p.envnames[longname] = envname
+}
+
+/*
+Bind all flags to environment variables derived from their long names, using the specified prefix.
+e.g. with prefix "MYAPP_", the flag `--log-level` is bound to `MYAPP_LOG_LEVEL`
+
+Flags bound explicitly with SetEnvVar() keep their explicit variable name.
+Sub-commands added afterwards inherit the prefix.
+*/
+func (p *Parser) SetEnvPrefix(prefix string) {
+	p.env_prefix = prefix
+}
+
+// the environment variable bound to the flag, or the empty string if there is none
+func (p *Parser) envName(longname string) string {
+	if envname, ok := p.envnames[longname]; ok {
+		return envname
+	}
+	if p.env_prefix != "" {
+		return p.env_prefix + strings.ToUpper(strings.ReplaceAll(longname, "-", "_"))
+	}
+	return ""
+}
+
+// assign environment variable values to each bound flag that was neither seen on the command line nor overridden
+func (p *Parser) applyEnv(seen map[string]bool, overridden map[string]bool) error {
+	for _, name := range p.longnames {
+		envname := p.envName(name)
+		if envname == "" || seen[name] || overridden[name] {
+			continue
+		}
+		value, ok := os.LookupEnv(envname)
+		if !ok || value == "" {
+			continue
+		}
+		if err := p.definitions[name].assign(value); err != nil {
+			return fmt.Errorf("from $%s: %w", envname, p.valueError(name, value, err))
+		}
+		p.markSet(seen, name, SOURCE_ENV, -1)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package goargs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_EnvVars(t *testing.T) {
+	t.Setenv("MYAPP_HOST", "example.com")
+	t.Setenv("MYAPP_PORT", "8080")
+	t.Setenv("MYAPP_LOG_LEVEL", "debug")
+	t.Setenv("APP_TIMEOUT", "5s")
+	t.Setenv("MYAPP_DEBUG", "true")
+	t.Setenv("MYAPP_VERBOSE", "2")
+
+	parser := NewParser("")
+	parser.SetEnvPrefix("MYAPP_")
+
+	host := parser.String("host", "localhost", "help")
+	port := parser.Int("port", 80, "help")
+	level := parser.Choices("log-level", []string{"info", "debug"}, "help")
+	timeout := parser.Duration("timeout", time.Second, "help")
+	parser.SetEnvVar("timeout", "APP_TIMEOUT")
+	debug := parser.Bool("debug", false, "help")
+	verbose := parser.Count("verbose", "help")
+
+	if err := parser.Parse([]string{"--port", "9090"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, "example.com", *host)
+	gocheck.Equal(t, 9090, *port)
+	gocheck.Equal(t, "debug", *level)
+	gocheck.Equal(t, 5*time.Second, *timeout)
+	gocheck.Equal(t, true, *debug)
+	gocheck.Equal(t, 2, *verbose)
+}
+
+func Test_EnvVars_Invalid(t *testing.T) {
+	t.Setenv("LEVEL", "loud")
+
+	parser := NewParser("")
+	level := parser.Choices("level", []string{"quiet", "normal"}, "help")
+	parser.SetEnvVar("level", "LEVEL")
+
+	if err := parser.Parse([]string{}); err == nil {
+		t.Errorf("Invalid environment value should have failed, got '%s'", *level)
+	}
+	if err := parser.Parse([]string{"--level", "quiet"}); err != nil {
+		t.Errorf("Command line value should take precedence: %v", err)
+	}
+	gocheck.Equal(t, "quiet", *level)
+}
+
+func Test_EnvVars_Help(t *testing.T) {
+	parser := NewParser("")
+	parser.SetEnvPrefix("MYAPP_")
+	parser.String("log-file", "out.log", "Where to log")
+
+	expect := strings.Join([]string{
+		"",
+		"",
+		"  --log-file STRING",
+		"    default: out.log",
+		"    env: MYAPP_LOG_FILE",
+		"    Where to log",
+	}, "\n")
+	if helptext := parser.SPrintHelp(); helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
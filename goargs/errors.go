@@ -0,0 +1,159 @@
+package goargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingFlagsError is returned by Parse() when required flags were not supplied.
+// `Flags` holds the long names of every missing flag, in declaration order.
+type MissingFlagsError struct {
+	Flags []string
+}
+
+func (e *MissingFlagsError) Error() string {
+	return fmt.Sprintf("missing required flags: --%s", strings.Join(e.Flags, ", --"))
+}
+
+// PositionalCountError is returned by Parse() when the number of positional arguments
+// is outside of the declared bounds. A negative `Max` means there is no maximum.
+type PositionalCountError struct {
+	Min int
+	Max int
+	Got int
+}
+
+func (e *PositionalCountError) Error() string {
+	if e.Got < e.Min {
+		return fmt.Sprintf("expected at least %d positional arguments, got %d", e.Min, e.Got)
+	}
+	return fmt.Sprintf("expected at most %d positional arguments, got %d", e.Max, e.Got)
+}
+
+// UnknownFlagError is returned when a flag is not defined, and flag definitions are required.
+// `Flag` is the flag name without leading hyphens; `Short` indicates a short flag.
+// `Suggestions` holds the similar flag names, without leading hyphens, if any.
+type UnknownFlagError struct {
+	Flag        string
+	Short       bool
+	Suggestions []string
+}
+
+func (e *UnknownFlagError) Error() string {
+	if e.Short {
+		if len(e.Suggestions) > 0 {
+			return fmt.Sprintf("unknown short flag '%s' (did you mean %s?)", e.Flag, alternatives(e.Suggestions, "-", ""))
+		}
+		return fmt.Sprintf("unknown short flag '%s'", e.Flag)
+	}
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("unknown flag --%s (did you mean %s?)", e.Flag, alternatives(e.Suggestions, "--", ""))
+	}
+	return fmt.Sprintf("unknown flag --%s", e.Flag)
+}
+
+// MissingValueError is returned when a value-taking flag is the last token.
+// `Flag` is the long name of the flag, and `Token` the flag token as it was specified.
+type MissingValueError struct {
+	Flag  string
+	Token string
+}
+
+func (e *MissingValueError) Error() string {
+	return fmt.Sprintf("expected value after %s", e.Token)
+}
+
+// InvalidValueError is returned when a value cannot be parsed for its destination type.
+// `Flag` is the long name of the flag, or empty when unpacking positional tokens,
+// `Token` is the offending value, and `Err` the underlying cause.
+type InvalidValueError struct {
+	Flag  string
+	Token string
+	Err   error
+}
+
+func (e *InvalidValueError) Error() string {
+	if e.Flag == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("invalid value '%s' for --%s: %v", e.Token, e.Flag, e.Err)
+}
+
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidChoiceError is returned when a Choices or Mode flag receives a value it does not accept.
+// `Mode` is set for Mode flags, whose valid choices are their modes.
+// `Suggestions` holds the similar choices, if any.
+type InvalidChoiceError struct {
+	Flag        string
+	Value       string
+	Choices     []string
+	Mode        bool
+	Suggestions []string
+}
+
+func (e *InvalidChoiceError) Error() string {
+	message := fmt.Sprintf("Invalid choice '%s'. Valid choices: %v", e.Value, e.Choices)
+	if e.Mode {
+		message = fmt.Sprintf("Invalid mode '%s' - choose from: %s", e.Value, strings.Join(e.Choices, ", "))
+	}
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("%s. Did you mean %s?", message, alternatives(e.Suggestions, "'", "'"))
+	}
+	return message
+}
+
+// ArityMismatchError is returned by UnpackExactly() when the number of tokens
+// does not match the number of variables to populate.
+type ArityMismatchError struct {
+	Expected int
+	Got      int
+}
+
+func (e *ArityMismatchError) Error() string {
+	return fmt.Sprintf("Mismatch number of tokens (%d) to number of variables to populate (%d)", e.Got, e.Expected)
+}
+
+// ConflictingFlagsError is returned by Parse() when flags that cannot be used together were supplied,
+// see SetMutuallyExclusive() and SetExactlyOne(). `Flags` holds the long names of the supplied flags.
+type ConflictingFlagsError struct {
+	Flags []string
+}
+
+func (e *ConflictingFlagsError) Error() string {
+	return fmt.Sprintf("flags cannot be used together: --%s", strings.Join(e.Flags, ", --"))
+}
+
+// MissingOneOfError is returned by Parse() when none of a set of flags was supplied, see SetExactlyOne().
+// `Flags` holds the long names of the flags of the set.
+type MissingOneOfError struct {
+	Flags []string
+}
+
+func (e *MissingOneOfError) Error() string {
+	return fmt.Sprintf("one of these flags is required: --%s", strings.Join(e.Flags, ", --"))
+}
+
+// DependentFlagError is returned by Parse() when a flag was supplied without the flags it requires,
+// see SetRequires(). `Flag` is the long name of the supplied flag, and `Missing` those of the missing flags.
+type DependentFlagError struct {
+	Flag    string
+	Missing []string
+}
+
+func (e *DependentFlagError) Error() string {
+	return fmt.Sprintf("--%s requires --%s", e.Flag, strings.Join(e.Missing, ", --"))
+}
+
+// AmbiguousFlagError is returned by Parse() when an abbreviated long flag matches several flags,
+// see AllowAbbreviations(). `Flag` is the abbreviation, and `Candidates` the matching long names.
+type AmbiguousFlagError struct {
+	Flag       string
+	Candidates []string
+}
+
+func (e *AmbiguousFlagError) Error() string {
+	return fmt.Sprintf("ambiguous flag --%s could be: --%s", e.Flag, strings.Join(e.Candidates, ", --"))
+}
@@ -0,0 +1,115 @@
+package goargs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Errors_Parse(t *testing.T) {
+	parser := NewParser("")
+	parser.Int("age", -1, "help")
+	parser.SetShortFlag('a', "age")
+	parser.Choices("dish", []string{"rice", "noodles"}, "help")
+	parser.Mode("style", "chinese", map[rune]string{'c': "chinese", 'j': "japanese"}, "help")
+
+	var unknown *UnknownFlagError
+	err := parser.Parse([]string{"--unknown=1"})
+	if !errors.As(err, &unknown) {
+		t.Errorf("Expected UnknownFlagError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "unknown", unknown.Flag)
+		gocheck.Equal(t, false, unknown.Short)
+		gocheck.Equal(t, "unknown flag --unknown", err.Error())
+	}
+
+	err = parser.Parse([]string{"-x"})
+	if !errors.As(err, &unknown) {
+		t.Errorf("Expected UnknownFlagError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "x", unknown.Flag)
+		gocheck.Equal(t, true, unknown.Short)
+		gocheck.Equal(t, "unknown short flag 'x'", err.Error())
+	}
+
+	var missing *MissingValueError
+	err = parser.Parse([]string{"-a"})
+	if !errors.As(err, &missing) {
+		t.Errorf("Expected MissingValueError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "age", missing.Flag)
+		gocheck.Equal(t, "expected value after -a", err.Error())
+	}
+
+	var invalid *InvalidValueError
+	err = parser.Parse([]string{"--age", "old"})
+	if !errors.As(err, &invalid) {
+		t.Errorf("Expected InvalidValueError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "age", invalid.Flag)
+		gocheck.Equal(t, "old", invalid.Token)
+		gocheck.Equal(t, "invalid value 'old' for --age: Could not parse old", err.Error())
+	}
+
+	var choice *InvalidChoiceError
+	err = parser.Parse([]string{"--dish", "bread"})
+	if !errors.As(err, &choice) {
+		t.Errorf("Expected InvalidChoiceError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "dish", choice.Flag)
+		gocheck.Equal(t, "bread", choice.Value)
+		gocheck.EqualArr(t, []string{"rice", "noodles"}, choice.Choices)
+		gocheck.Equal(t, "Invalid choice 'bread'. Valid choices: [rice noodles]", err.Error())
+	}
+
+	err = parser.Parse([]string{"--style", "korean"})
+	if !errors.As(err, &choice) {
+		t.Errorf("Expected InvalidChoiceError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "style", choice.Flag)
+		gocheck.EqualArr(t, []string{"chinese", "japanese"}, choice.Choices)
+		gocheck.Equal(t, true, choice.Mode)
+		gocheck.Equal(t, "Invalid mode 'korean' - choose from: chinese, japanese", err.Error())
+	}
+}
+
+func Test_Errors_Env(t *testing.T) {
+	t.Setenv("AGE", "old")
+
+	parser := NewParser("")
+	parser.Int("age", -1, "help")
+	parser.SetEnvVar("age", "AGE")
+
+	var invalid *InvalidValueError
+	if err := parser.Parse([]string{}); !errors.As(err, &invalid) {
+		t.Errorf("Expected InvalidValueError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "age", invalid.Flag)
+		gocheck.Equal(t, "old", invalid.Token)
+	}
+}
+
+func Test_Errors_Unpack(t *testing.T) {
+	var name string
+	var count int
+
+	var arity *ArityMismatchError
+	err := UnpackExactly([]string{"Alex"}, &name, &count)
+	if !errors.As(err, &arity) {
+		t.Errorf("Expected ArityMismatchError, got: %v", err)
+	} else {
+		gocheck.Equal(t, 2, arity.Expected)
+		gocheck.Equal(t, 1, arity.Got)
+		gocheck.Equal(t, "Mismatch number of tokens (1) to number of variables to populate (2)", err.Error())
+	}
+
+	var invalid *InvalidValueError
+	err = UnpackExactly([]string{"Alex", "many"}, &name, &count)
+	if !errors.As(err, &invalid) {
+		t.Errorf("Expected InvalidValueError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "", invalid.Flag)
+		gocheck.Equal(t, "many", invalid.Token)
+	}
+}
@@ -0,0 +1,66 @@
+package goargs
+
+import (
+	"encoding"
+	"fmt"
+	"time"
+)
+
+/*
+Register a flag of type T, storing to the supplied `value *T` pointer
+Dispatches to the registration function of the type:
+
+* string, int, int64, uint, float32, float64, bool, time.Duration : like StringVar() etc
+* []string : like AppenderVar(), starting with `defval` values
+* types implementing encoding.TextUnmarshaler and encoding.TextMarshaler : like TextVar()
+* types whose pointer implements Value : like Var()
+
+Panics if the type is not supported.
+*/
+func FlagVar[T any](p *Parser, value *T, name string, defval T, helpstr string) {
+	switch ptr := any(value).(type) {
+	case *string:
+		p.StringVar(ptr, name, any(defval).(string), helpstr)
+	case *int:
+		p.IntVar(ptr, name, any(defval).(int), helpstr)
+	case *int64:
+		p.Int64Var(ptr, name, any(defval).(int64), helpstr)
+	case *uint:
+		p.UintVar(ptr, name, any(defval).(uint), helpstr)
+	case *float32:
+		p.FloatVar(ptr, name, any(defval).(float32), helpstr)
+	case *float64:
+		p.Float64Var(ptr, name, any(defval).(float64), helpstr)
+	case *bool:
+		p.BoolVar(ptr, name, any(defval).(bool), helpstr)
+	case *time.Duration:
+		p.DurationVar(ptr, name, any(defval).(time.Duration), helpstr)
+	case *[]string:
+		*ptr = any(defval).([]string)
+		p.AppenderVar(ptr, name, helpstr)
+	case encoding.TextUnmarshaler:
+		marshaler, ok := any(defval).(encoding.TextMarshaler)
+		if !ok {
+			marshaler, ok = any(&defval).(encoding.TextMarshaler)
+		}
+		if !ok {
+			panic(fmt.Sprintf("Flag type %T implements encoding.TextUnmarshaler but not encoding.TextMarshaler", defval))
+		}
+		p.TextVar(ptr, name, marshaler, helpstr)
+	case Value:
+		*value = defval
+		p.Var(ptr, name, helpstr)
+	default:
+		panic(fmt.Sprintf("Unsupported flag type %T", defval))
+	}
+}
+
+// Register a flag of type T, storing to the returned `*T` pointer
+// See FlagVar() for supported types.
+//
+// e.g. `port := goargs.Flag(&parser, "port", 8080, "Port to listen on")`
+func Flag[T any](p *Parser, name string, defval T, helpstr string) *T {
+	var val T
+	FlagVar(p, &val, name, defval, helpstr)
+	return &val
+}
@@ -0,0 +1,52 @@
+package goargs
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Flag(t *testing.T) {
+	parser := NewParser("")
+	name := Flag(&parser, "name", "nobody", "help")
+	port := Flag(&parser, "port", 80, "help")
+	ratio := Flag(&parser, "ratio", float32(0.5), "help")
+	admit := Flag(&parser, "admit", false, "help")
+	wait := Flag(&parser, "wait", time.Second, "help")
+	tags := Flag(&parser, "tag", []string{"default"}, "help")
+	level := Flag(&parser, "level", slog.LevelInfo, "help")
+	origin := Flag(&parser, "origin", t_Point{1, 1}, "help")
+
+	gocheck.Equal(t, "nobody", *name)
+	gocheck.Equal(t, 80, *port)
+	gocheck.EqualArr(t, []string{"default"}, *tags)
+	gocheck.Equal(t, t_Point{1, 1}, *origin)
+
+	args := []string{"--name", "Alex", "--port", "8080", "--ratio", "0.25", "--admit", "--wait", "2s",
+		"--tag", "extra", "--level", "error", "--origin", "2,3"}
+	if err := parser.Parse(args); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, "Alex", *name)
+	gocheck.Equal(t, 8080, *port)
+	gocheck.Equal(t, 0.25, *ratio)
+	gocheck.Equal(t, true, *admit)
+	gocheck.Equal(t, 2*time.Second, *wait)
+	gocheck.EqualArr(t, []string{"default", "extra"}, *tags)
+	gocheck.Equal(t, slog.LevelError, *level)
+	gocheck.Equal(t, t_Point{2, 3}, *origin)
+}
+
+func Test_Flag_Unsupported(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Unsupported type should have panicked")
+		}
+	}()
+	parser := NewParser("")
+	Flag(&parser, "size", complex(1, 1), "help")
+}
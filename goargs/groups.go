@@ -0,0 +1,75 @@
+package goargs
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The heading of the help section listing flags not assigned to any group
+const _OTHER_OPTIONS = "Other options"
+
+// A titled list of flag names, as laid out in help
+type t_HelpSection struct {
+	title string
+	names []string
+}
+
+/*
+Assign existing flags to a named group, such as "Networking" or "Logging".
+Help lists each group under its own heading, in the order groups were first used, followed by
+ungrouped flags under "Other options". Flags keep their declaration order within a group.
+Assigning a flag again moves it to the new group.
+Panics if a long flag is not yet registered.
+*/
+func (p *Parser) SetGroup(group string, names ...string) {
+	for _, name := range names {
+		if _, ok := p.definitions[name]; !ok {
+			panic(fmt.Sprintf("Flag '--%s' not yet defined", name))
+		}
+		p.groups[name] = group
+	}
+	if !slices.Contains(p.groupnames, group) {
+		p.groupnames = append(p.groupnames, group)
+	}
+}
+
+// The names of the groups, in the order they were first used
+func (p *Parser) Groups() []string {
+	return slices.Clone(p.groupnames)
+}
+
+// The group a flag is assigned to, or the empty string for ungrouped flags
+func (p *Parser) FlagGroup(name string) string {
+	return p.groups[name]
+}
+
+// The long names of the flags in a group, in declaration order. The empty string lists ungrouped flags.
+func (p *Parser) GroupFlags(group string) []string {
+	names := []string{}
+	for _, name := range p.longnames {
+		if p.groups[name] == group {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// the flag sections to lay out in help. Without groups, a single untitled section lists all flags.
+func (p *Parser) helpSections() []t_HelpSection {
+	if len(p.groupnames) == 0 {
+		return []t_HelpSection{{"", p.listedNames()}}
+	}
+
+	sections := []t_HelpSection{}
+	for _, group := range append(p.Groups(), "") {
+		names := slices.DeleteFunc(p.GroupFlags(group), func(name string) bool { return p.hidden[name] })
+		if len(names) == 0 {
+			continue
+		}
+		if group == "" {
+			group = _OTHER_OPTIONS
+		}
+		sections = append(sections, t_HelpSection{group, names})
+	}
+	return sections
+}
@@ -0,0 +1,90 @@
+package goargs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Groups(t *testing.T) {
+	parser := NewParser("serve")
+	parser.String("host", "localhost", "Address to listen on")
+	parser.Int("port", 8080, "Port to listen on")
+	parser.Bool("dry-run", false, "Do nothing")
+	parser.String("log-file", "-", "Where to log")
+	parser.SetGroup("Networking", "host", "port")
+	parser.SetGroup("Logging", "log-file")
+
+	gocheck.EqualArr(t, []string{"Networking", "Logging"}, parser.Groups())
+	gocheck.EqualArr(t, []string{"host", "port"}, parser.GroupFlags("Networking"))
+	gocheck.EqualArr(t, []string{"dry-run"}, parser.GroupFlags(""))
+	gocheck.Equal(t, "Logging", parser.FlagGroup("log-file"))
+	gocheck.Equal(t, "", parser.FlagGroup("dry-run"))
+
+	parser.SetGroup("Logging", "port")
+	gocheck.EqualArr(t, []string{"host"}, parser.GroupFlags("Networking"))
+	gocheck.EqualArr(t, []string{"port", "log-file"}, parser.GroupFlags("Logging"))
+}
+
+func Test_Groups_Help(t *testing.T) {
+	parser := NewParser("serve")
+	parser.String("host", "localhost", "Address to listen on")
+	parser.Int("port", 8080, "Port to listen on")
+	parser.Bool("dry-run", false, "Do nothing")
+	parser.String("log-file", "-", "Where to log")
+	parser.SetGroup("Networking", "host", "port")
+	parser.SetGroup("Logging", "log-file")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"serve",
+		"",
+		"Networking:",
+		"  --host STRING",
+		"    default: localhost",
+		"    Address to listen on",
+		"  --port INT",
+		"    default: 8080",
+		"    Port to listen on",
+		"",
+		"Logging:",
+		"  --log-file STRING",
+		"    default: -",
+		"    Where to log",
+		"",
+		"Other options:",
+		"  --dry-run",
+		"    default: false",
+		"    Do nothing",
+	}, "\n")
+	gocheck.EqualArr(t, strings.Split(expect, "\n"), strings.Split(helptext, "\n"))
+
+	parser.SetHelpLayout(HELP_LAYOUT_COMPACT)
+	parser.SetHelpWidth(80)
+	helptext = parser.SPrintHelp()
+	expect = strings.Join([]string{
+		"serve",
+		"",
+		"Networking:",
+		"      --host STRING      Address to listen on (default: localhost)",
+		"      --port INT         Port to listen on (default: 8080)",
+		"",
+		"Logging:",
+		"      --log-file STRING  Where to log (default: -)",
+		"",
+		"Other options:",
+		"      --dry-run          Do nothing (default: false)",
+	}, "\n")
+	gocheck.EqualArr(t, strings.Split(expect, "\n"), strings.Split(helptext, "\n"))
+}
+
+func Test_Groups_Undefined(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Undefined flag should have panicked")
+		}
+	}()
+	parser := NewParser("")
+	parser.SetGroup("Networking", "nope")
+}
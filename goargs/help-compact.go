@@ -0,0 +1,253 @@
+package goargs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type HelpLayout int
+
+const (
+	// Each flag over several lines, with its help string indented below it
+	HELP_LAYOUT_DEFAULT HelpLayout = iota
+	// GNU-style aligned columns, one flag per row, with help strings wrapped to the help width
+	HELP_LAYOUT_COMPACT
+)
+
+// The widest the flags column can get in the compact layout before help strings start on the next line
+const _HELP_MAX_COLUMN = 32
+
+// The narrowest help strings can get in the compact layout, regardless of the help width
+const _HELP_MIN_WRAP = 20
+
+/*
+Choose how SPrintHelp() lays out flags, positionals and sub-commands.
+Sub-commands added afterwards inherit the layout.
+*/
+func (p *Parser) SetHelpLayout(layout HelpLayout) {
+	p.help_layout = layout
+}
+
+/*
+Set the width to which the compact help layout wraps its help strings.
+Sub-commands added afterwards inherit the width.
+A width of 0 (the default) uses `$COLUMNS` if it is set, or 80.
+*/
+func (p *Parser) SetHelpWidth(width int) {
+	p.help_width = width
+}
+
+func (p *Parser) helpWidth() int {
+	if p.help_width > 0 {
+		return p.help_width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 80
+}
+
+// Split text into lines of at most `width` characters at spaces.
+// Words longer than `width` are left on a line of their own.
+func wrapText(text string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line == "" {
+			line = word
+		} else if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	return append(lines, line)
+}
+
+// A row of the compact help layout: the text of the left column, and the paragraphs of the right column
+type t_HelpRow struct {
+	left       string
+	paragraphs []string
+}
+
+// append notes in brackets to the last paragraph
+func withNotes(paragraphs []string, notes []string) []string {
+	if len(notes) == 0 {
+		return paragraphs
+	}
+	last := len(paragraphs) - 1
+	paragraphs[last] = strings.TrimSpace(fmt.Sprintf("%s (%s)", paragraphs[last], strings.Join(notes, "; ")))
+	return paragraphs
+}
+
+func (p *Parser) compactFlagRow(name string) t_HelpRow {
+	def := p.definitions[name]
+	paragraphs := strings.Split(def.getHelpString(), "\n")
+	shorts := []string{}
+	notes := []string{}
+
+	if mode, ismode := def.(def_Mode); ismode {
+		// the help string ends with a line per mode, which we render ourselves
+		paragraphs = paragraphs[:len(paragraphs)-len(mode.modes)]
+	} else {
+		for _, short := range p.shortFlagsFor(name) {
+			shorts = append(shorts, fmt.Sprintf("-%c, ", short))
+		}
+	}
+
+	left := fmt.Sprintf("  %s%s", strings.Join(shorts, ""), p.longFlagsLabel(name))
+	if len(shorts) == 0 {
+		left = fmt.Sprintf("      %s", p.longFlagsLabel(name))
+	}
+	left += p.valueLabel(name)
+
+	if p.required[name] {
+		notes = append(notes, "required")
+	}
+	switch def.(type) {
+	case def_Count:
+		notes = append(notes, "counted")
+	case def_Appender:
+		notes = append(notes, "repeatable")
+	case def_Func:
+		// do nothing. the user help will explain all.
+	default:
+		defval, ok := defaultString(def)
+		if !ok {
+			panic(fmt.Sprintf("Internal error (goargs): Uncatered type '%t'", def))
+		}
+		notes = append(notes, fmt.Sprintf("default: %s", defval))
+		if choices, ok := def.(def_Choices); ok {
+			notes = append(notes, fmt.Sprintf("choices: %s", strings.Join(choices.choices, ", ")))
+		}
+	}
+	if implicit, ok := p.implicit_values[name]; ok {
+		notes = append(notes, fmt.Sprintf("without value: %s", implicit))
+	}
+	if envname := p.envName(name); envname != "" {
+		notes = append(notes, fmt.Sprintf("env: %s", envname))
+	}
+	paragraphs = withNotes(paragraphs, notes)
+
+	if mode, ismode := def.(def_Mode); ismode {
+		for _, short := range p.shortFlagsFor(name) {
+			paragraphs = append(paragraphs, fmt.Sprintf("  -%c : %s", short, mode.modes[short]))
+		}
+	}
+	return t_HelpRow{left, paragraphs}
+}
+
+func (p *Parser) compactPositionalRow(pdef t_Positional) t_HelpRow {
+	name := pdef.def.getName()
+	if pdef.variadic {
+		name += "..."
+	}
+	notes := []string{}
+	if pdef.optional {
+		notes = append(notes, "optional")
+	}
+	if choices, ok := pdef.def.(def_Choices); ok {
+		notes = append(notes, fmt.Sprintf("choices: %s", strings.Join(choices.choices, ", ")))
+	}
+	return t_HelpRow{"  " + name, withNotes(strings.Split(pdef.def.getHelpString(), "\n"), notes)}
+}
+
+// Render rows in two columns, wrapping the right column to the help width
+func (p *Parser) compactRows(rows []t_HelpRow, column int) []string {
+	wrap := max(p.helpWidth()-column, _HELP_MIN_WRAP)
+	indent := strings.Repeat(" ", column)
+
+	helplines := []string{}
+	for _, row := range rows {
+		left := row.left
+		if utf8.RuneCountInString(left)+2 > column {
+			helplines = append(helplines, left)
+			left = ""
+		}
+		for _, paragraph := range row.paragraphs {
+			// keep the leading indentation of the paragraph on each of its lines
+			trimmed := strings.TrimLeft(paragraph, " ")
+			pindent := paragraph[:len(paragraph)-len(trimmed)]
+			for _, line := range wrapText(trimmed, wrap-len(pindent)) {
+				if left == "" {
+					helplines = append(helplines, strings.TrimRight(indent+pindent+line, " "))
+				} else {
+					helplines = append(helplines, strings.TrimRight(fmt.Sprintf("%-*s%s%s", column, left, pindent, line), " "))
+					left = ""
+				}
+			}
+		}
+		if left != "" {
+			helplines = append(helplines, left)
+		}
+	}
+	return helplines
+}
+
+// Produce the help text in the compact layout
+func (p *Parser) sprintCompactHelp() string {
+	sections := p.helpSections()
+	sectionrows := [][]t_HelpRow{}
+	flagrows := []t_HelpRow{}
+	for _, section := range sections {
+		rows := []t_HelpRow{}
+		for _, name := range section.names {
+			rows = append(rows, p.compactFlagRow(name))
+		}
+		sectionrows = append(sectionrows, rows)
+		flagrows = append(flagrows, rows...)
+	}
+	positionalrows := []t_HelpRow{}
+	for _, pdef := range p.positional_defs {
+		positionalrows = append(positionalrows, p.compactPositionalRow(pdef))
+	}
+	commandrows := []t_HelpRow{}
+	for _, verb := range p.commandnames {
+		commandrows = append(commandrows, t_HelpRow{"  " + verb, []string{strings.SplitN(p.commands[verb].helptext, "\n", 2)[0]}})
+	}
+
+	// align all sections on the same column
+	column := 0
+	for _, rows := range [][]t_HelpRow{flagrows, positionalrows, commandrows} {
+		for _, row := range rows {
+			if width := utf8.RuneCountInString(row.left) + 2; width <= _HELP_MAX_COLUMN {
+				column = max(column, width)
+			}
+		}
+	}
+	if column == 0 {
+		column = _HELP_MAX_COLUMN
+	}
+
+	helplines := p.helpHeaderLines()
+	for i, section := range sections {
+		if len(section.names) == 0 {
+			continue
+		}
+		title := section.title
+		if title == "" {
+			title = "Options"
+		}
+		helplines = append(helplines, "", title+":")
+		helplines = append(helplines, p.compactRows(sectionrows[i], column)...)
+	}
+	helplines = append(helplines, p.constraintHelpLines()...)
+	if len(positionalrows) > 0 {
+		helplines = append(helplines, "", fmt.Sprintf("Arguments: %s", p.positionalUsage()))
+		helplines = append(helplines, p.compactRows(positionalrows, column)...)
+	}
+	if len(commandrows) > 0 {
+		helplines = append(helplines, "", "Commands:")
+		helplines = append(helplines, p.compactRows(commandrows, column)...)
+	}
+
+	if len(p.post_helptext) > 0 {
+		helplines = append(helplines, "", p.post_helptext)
+	}
+
+	return strings.Join(helplines, "\n")
+}
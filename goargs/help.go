@@ -22,72 +22,129 @@ func typeName(def t_VarDef) string {
 	return strings.ToUpper(typename[4:])
 }
 
-// Produce help text string and return it.
+// the name of the type of value a definition takes, as shown in help
+func valueTypeName(def t_VarDef) string {
+	switch def.(type) {
+	case def_Choices, def_Appender, def_Func, def_Mode:
+		return "STRING"
+	case def_Count:
+		return "INT"
+	case def_Value:
+		return strings.ToUpper(def.(def_Value).value.Type())
+	case def_Text:
+		return def.(def_Text).typeName()
+	default:
+		return typeName(def)
+	}
+}
+
+// the value placeholder following a flag's long names in help: " TYPE", "[=TYPE]" with an implicit value,
+// or nothing for switch-like flags
+func (p *Parser) valueLabel(name string) string {
+	def := p.definitions[name]
+	if _, ok := p.implicit_values[name]; ok {
+		return fmt.Sprintf("[=%s]", valueTypeName(def))
+	} else if takesValue(def) {
+		return " " + valueTypeName(def)
+	}
+	return ""
+}
+
+// the default value of a definition as shown in help, if the definition type has one
+func defaultString(def t_VarDef) (string, bool) {
+	switch def.(type) {
+	case def_String:
+		return def.(def_String).defval, true
+	case def_Choices:
+		return def.(def_Choices).choices[0], true
+	case def_Int:
+		return fmt.Sprintf("%d", def.(def_Int).defval), true
+	case def_Int64:
+		return fmt.Sprintf("%d", def.(def_Int64).defval), true
+	case def_Uint:
+		return fmt.Sprintf("%d", def.(def_Uint).defval), true
+	case def_Float:
+		return fmt.Sprintf("%f", def.(def_Float).defval), true
+	case def_Float64:
+		return fmt.Sprintf("%f", def.(def_Float64).defval), true
+	case def_Bool:
+		return fmt.Sprintf("%t", def.(def_Bool).defval), true
+	case def_Duration:
+		return fmt.Sprintf("%v", def.(def_Duration).defval), true
+	case def_Mode:
+		return def.(def_Mode).defval, true
+	case def_Value:
+		return def.(def_Value).defval, true
+	case def_Text:
+		return def.(def_Text).defaultText(), true
+	}
+	return "", false
+}
+
+/*
+Set the program name, to start the help with the usage line from SPrintUsage(), before the help text.
+Sub-commands, including those added afterwards, show their verb after the program name, e.g. `tool db migrate`.
+*/
+func (p *Parser) SetProgramName(progname string) {
+	p.program_name = progname
+	for _, name := range p.commandnames {
+		p.commands[name].SetProgramName(progname + " " + name)
+	}
+}
+
+/*
+Produce the usage line generated from the definitions, e.g. `Usage: tool [OPTIONS] NAME [AGE] FILES...`
+`[OPTIONS]` is shown if any flags are listed in help, and `COMMAND ...` if sub-commands are registered.
+*/
+func (p *Parser) SPrintUsage(progname string) string {
+	tokens := []string{"Usage:", progname}
+	if len(p.listedNames()) > 0 {
+		tokens = append(tokens, "[OPTIONS]")
+	}
+	if usage := p.positionalUsage(); usage != "" {
+		tokens = append(tokens, usage)
+	}
+	if len(p.commandnames) > 0 {
+		tokens = append(tokens, "COMMAND ...")
+	}
+	return strings.Join(tokens, " ")
+}
+
+// the first lines of help: the usage line if a program name is set, and the help text
+func (p *Parser) helpHeaderLines() []string {
+	if p.program_name == "" {
+		return []string{p.helptext}
+	} else if p.helptext == "" {
+		return []string{p.SPrintUsage(p.program_name)}
+	}
+	return []string{p.SPrintUsage(p.program_name), "", p.helptext}
+}
+
+// Produce help text string and return it, in the layout set by SetHelpLayout().
 // Panics if an unknown type is unimplemented (goargs developer error. please report it!)
 func (p *Parser) SPrintHelp() string {
+	if p.help_layout == HELP_LAYOUT_COMPACT {
+		return p.sprintCompactHelp()
+	}
+
 	// return a string of formatted help information
-	helplines := []string{p.helptext, ""}
-	for _, name := range p.longnames {
-		def := p.definitions[name]
-
-		// Flag format
-		switch def.(type) {
-		case def_Bool, def_Count:
-			helplines = append(helplines, fmt.Sprintf("  --%s", name))
-			if sflag, err := p.runeFromLong(name); err == nil {
-				helplines = append(helplines, fmt.Sprintf("  -%c", sflag))
-			}
-		default:
-			var tname string
-			switch def.(type) {
-			case def_Choices, def_Appender, def_Func, def_Mode:
-				tname = "STRING"
-			default:
-				tname = typeName(def)
-			}
-			helplines = append(helplines, fmt.Sprintf("  --%s %s", name, tname))
-			switch def.(type) {
-			case def_Mode:
-				helplines = append(helplines, "    (use short flag or STRING value)")
-			default:
-				if sflag, err := p.runeFromLong(name); err == nil {
-					helplines = append(helplines, fmt.Sprintf("  -%c %s", sflag, tname))
-				}
+	helplines := append(p.helpHeaderLines(), "")
+	for i, section := range p.helpSections() {
+		if section.title != "" {
+			if i > 0 {
+				helplines = append(helplines, "")
 			}
+			helplines = append(helplines, section.title+":")
 		}
-
-		// Flag default value
-		switch def.(type) {
-		case def_String:
-			helplines = append(helplines, fmt.Sprintf("    default: %s", def.(def_String).defval))
-		case def_Choices:
-			helplines = append(helplines, fmt.Sprintf("    default: %s", def.(def_Choices).choices[0]))
-			helplines = append(helplines, fmt.Sprintf("    choices: %s", strings.Join(def.(def_Choices).choices, ", ")))
-		case def_Int, def_Int64, def_Uint:
-			helplines = append(helplines, fmt.Sprintf("    default: %d", def.(def_Int).defval))
-		case def_Float, def_Float64:
-			helplines = append(helplines, fmt.Sprintf("    default: %f", def.(def_Float).defval))
-		case def_Bool:
-			helplines = append(helplines, fmt.Sprintf("    default: %t", def.(def_Bool).defval))
-		case def_Duration: // DurationDef, UnmarshalerDef
-			helplines = append(helplines, fmt.Sprintf("    default: %v", def.(def_Duration).defval))
-		case def_Count:
-			helplines = append(helplines, fmt.Sprintf("    (each appearance is counted)"))
-		case def_Appender:
-			helplines = append(helplines, fmt.Sprintf("    (can be specified multiple times)"))
-		case def_Mode:
-			helplines = append(helplines, fmt.Sprintf("    default: %s", def.(def_Mode).defval))
-		case def_Func:
-			// do nothing. the user help will explain all.
-		default:
-			panic(fmt.Sprintf("Internal error (goargs): Uncatered type '%t'", def))
+		for _, name := range section.names {
+			helplines = append(helplines, p.flagHelpLines(name)...)
 		}
-
-		// Flag help string
-		// TODO - wrap on terminal width splitting at spaces
-		helplines = append(helplines, fmt.Sprintf("    %s", def.getHelpString()))
 	}
 
+	helplines = append(helplines, p.constraintHelpLines()...)
+	helplines = append(helplines, p.positionalHelpLines()...)
+	helplines = append(helplines, p.commandHelpLines()...)
+
 	if len(p.post_helptext) > 0 {
 		helplines = append(helplines, p.post_helptext)
 	}
@@ -95,6 +152,65 @@ func (p *Parser) SPrintHelp() string {
 	return strings.Join(helplines, "\n")
 }
 
+// the help lines of a flag, in the default layout
+func (p *Parser) flagHelpLines(name string) []string {
+	def := p.definitions[name]
+	helplines := []string{}
+
+	// Flag format
+	_, hasimplicit := p.implicit_values[name]
+	helplines = append(helplines, fmt.Sprintf("  %s%s", p.longFlagsLabel(name), p.valueLabel(name)))
+	switch def.(type) {
+	case def_Bool, def_Count:
+		if sflag, err := p.runeFromLong(name); err == nil {
+			helplines = append(helplines, fmt.Sprintf("  -%c", sflag))
+		}
+	case def_Mode:
+		helplines = append(helplines, "    (use short flag or STRING value)")
+	default:
+		if sflag, err := p.runeFromLong(name); err == nil && hasimplicit {
+			helplines = append(helplines, fmt.Sprintf("  -%c[%s]", sflag, valueTypeName(def)))
+		} else if err == nil {
+			helplines = append(helplines, fmt.Sprintf("  -%c %s", sflag, valueTypeName(def)))
+		}
+	}
+
+	if p.required[name] {
+		helplines = append(helplines, "    (required)")
+	}
+
+	// Flag default value
+	switch def.(type) {
+	case def_Count:
+		helplines = append(helplines, fmt.Sprintf("    (each appearance is counted)"))
+	case def_Appender:
+		helplines = append(helplines, fmt.Sprintf("    (can be specified multiple times)"))
+	case def_Func:
+		// do nothing. the user help will explain all.
+	default:
+		defval, ok := defaultString(def)
+		if !ok {
+			panic(fmt.Sprintf("Internal error (goargs): Uncatered type '%t'", def))
+		}
+		helplines = append(helplines, fmt.Sprintf("    default: %s", defval))
+		if choices, ok := def.(def_Choices); ok {
+			helplines = append(helplines, fmt.Sprintf("    choices: %s", strings.Join(choices.choices, ", ")))
+		}
+	}
+
+	if implicit, ok := p.implicit_values[name]; ok {
+		helplines = append(helplines, fmt.Sprintf("    without value: %s", implicit))
+	}
+	if envname := p.envName(name); envname != "" {
+		helplines = append(helplines, fmt.Sprintf("    env: %s", envname))
+	}
+
+	// Flag help string. See HELP_LAYOUT_COMPACT for wrapping on terminal width
+	helplines = append(helplines, fmt.Sprintf("    %s", def.getHelpString()))
+
+	return helplines
+}
+
 /* Set the text to print at the end of the help message, after the parameters have been listed.
  */
 func (p *Parser) SetPostHelptext(text string) {
@@ -3,6 +3,8 @@ package goargs
 import (
 	"strings"
 	"testing"
+
+	"github.com/taikedz/gocheck"
 )
 
 func Test_helpstr(t *testing.T) {
@@ -80,3 +82,144 @@ func Test_helpst_special(t *testing.T) {
 		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
 	}
 }
+
+func Test_helpstr_required(t *testing.T) {
+	parser := NewParser("Whack-a-mole")
+
+	parser.String("gopher", "", "Wee rat")
+	parser.SetRequired("gopher")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"Whack-a-mole",
+		"",
+		"  --gopher STRING",
+		"    (required)",
+		"    default: ",
+		"    Wee rat",
+	}, "\n")
+	if helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
+
+func Test_helpstr_wide_types(t *testing.T) {
+	parser := NewParser("Sizes")
+
+	parser.Int64("big", 1, "Big")
+	parser.Uint("count", 2, "Count")
+	parser.Float64("ratio", 0.5, "Ratio")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"Sizes",
+		"",
+		"  --big INT64",
+		"    default: 1",
+		"    Big",
+		"  --count UINT",
+		"    default: 2",
+		"    Count",
+		"  --ratio FLOAT64",
+		"    default: 0.500000",
+		"    Ratio",
+	}, "\n")
+	if helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
+
+func Test_helpstr_compact(t *testing.T) {
+	parser := NewParser("Whack-a-mole")
+	parser.SetHelpLayout(HELP_LAYOUT_COMPACT)
+	parser.SetHelpWidth(60)
+
+	parser.String("gopher", "gaffer", "Wee rat")
+	parser.SetShortFlag('g', "gopher")
+	parser.SetRequired("gopher")
+	parser.Bool("whack", false, "Slam it? This is a long help string that will need to wrap over several lines of text")
+	parser.Count("hard", "How hard?")
+	parser.SetShortFlag('h', "hard")
+	parser.SetEnvVar("hard", "HARD")
+	parser.Choices("tool", []string{"rolling-pin", "hammer"}, "What to use?\nChoose wisely")
+	parser.Mode("damage", "blunt", map[rune]string{'b': "blunt", 's': "sharp"}, "Damage type")
+	parser.Appender("a-very-long-flag-name-indeed", "Acceptable squeaks")
+	parser.PositionalString("TARGET", "What to whack")
+	parser.AddCommand("run", "Run away\nfast")
+	parser.SetPostHelptext("Have fun")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"Whack-a-mole",
+		"",
+		"Options:",
+		"  -g, --gopher STRING  Wee rat (required; default: gaffer)",
+		"      --whack          Slam it? This is a long help string",
+		"                       that will need to wrap over several",
+		"                       lines of text (default: false)",
+		"  -h, --hard           How hard? (counted; env: HARD)",
+		"      --tool STRING    What to use?",
+		"                       Choose wisely (default: rolling-pin;",
+		"                       choices: rolling-pin, hammer)",
+		"      --damage STRING  Damage type (default: blunt)",
+		"                         -b : blunt",
+		"                         -s : sharp",
+		"      --a-very-long-flag-name-indeed STRING",
+		"                       Acceptable squeaks (repeatable)",
+		"",
+		"Arguments: TARGET",
+		"  TARGET               What to whack",
+		"",
+		"Commands:",
+		"  run                  Run away",
+		"",
+		"Have fun",
+	}, "\n")
+	if helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
+
+func Test_helpWidth(t *testing.T) {
+	parser := NewParser("")
+
+	t.Setenv("COLUMNS", "")
+	gocheck.Equal(t, 80, parser.helpWidth())
+	t.Setenv("COLUMNS", "132")
+	gocheck.Equal(t, 132, parser.helpWidth())
+	parser.SetHelpWidth(100)
+	gocheck.Equal(t, 100, parser.helpWidth())
+}
+
+func Test_wrapText(t *testing.T) {
+	gocheck.EqualArr(t, []string{"one two", "three", "four"}, wrapText("one two three four", 9))
+	gocheck.EqualArr(t, []string{"a", "unbreakable", "b"}, wrapText("a unbreakable b", 5))
+	gocheck.EqualArr(t, []string{""}, wrapText("", 10))
+}
+
+func Test_helpstr_implicit(t *testing.T) {
+	parser := NewParser("Whack-a-mole")
+	parser.Choices("color", []string{"never", "auto", "always"}, "Colour output")
+	parser.SetShortFlag('c', "color")
+	parser.SetImplicitValue("color", "auto")
+
+	gocheck.EqualArr(t, []string{
+		"Whack-a-mole",
+		"",
+		"  --color[=STRING]",
+		"  -c[STRING]",
+		"    default: never",
+		"    choices: never, auto, always",
+		"    without value: auto",
+		"    Colour output",
+	}, strings.Split(parser.SPrintHelp(), "\n"))
+
+	parser.SetHelpLayout(HELP_LAYOUT_COMPACT)
+	parser.SetHelpWidth(120)
+	gocheck.EqualArr(t, []string{
+		"Whack-a-mole",
+		"",
+		"Options:",
+		"  -c, --color[=STRING]  Colour output (default: never; choices: never, auto, always; without value: auto)",
+	}, strings.Split(parser.SPrintHelp(), "\n"))
+}
@@ -0,0 +1,152 @@
+package goargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// escape text for roff: backslashes, and control characters at the start of lines
+func roffEscape(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, `\`, `\e`), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escape a multi-line paragraph for roff, keeping its line breaks
+func roffParagraph(text string) string {
+	return strings.Join(strings.Split(roffEscape(text), "\n"), "\n.br\n")
+}
+
+// a flag token in roff bold, with escaped hyphens
+func roffFlag(flag string) string {
+	return `\fB` + strings.ReplaceAll(roffEscape(flag), "-", `\-`) + `\fR`
+}
+
+/*
+Produce a man page in roff (man(7)) format, for the program `progname` in manual `section`, and return it.
+
+* NAME : `progname`, and the one-line `summary`
+* SYNOPSIS : the parser's help text
+* OPTIONS : the flags, in declaration order
+* ARGUMENTS and COMMANDS : the declared positionals and the sub-commands, if any
+* NOTES : the post help text, if set
+
+The output is deterministic, and carries no date.
+*/
+func (p *Parser) SPrintManPage(progname string, section int, summary string) string {
+	lines := []string{
+		fmt.Sprintf(".TH %s %d", strings.ToUpper(roffEscape(progname)), section),
+		".SH NAME",
+		fmt.Sprintf(`%s \- %s`, roffEscape(progname), roffEscape(summary)),
+		".SH SYNOPSIS",
+		".nf",
+		roffEscape(p.helptext),
+		".fi",
+	}
+
+	if len(p.listedNames()) > 0 {
+		lines = append(lines, ".SH OPTIONS")
+		for _, name := range p.listedNames() {
+			lines = append(lines, p.manFlagLines(name)...)
+		}
+	}
+
+	if len(p.positional_defs) > 0 {
+		lines = append(lines, ".SH ARGUMENTS")
+		for _, pdef := range p.positional_defs {
+			token := pdef.def.getName()
+			if pdef.variadic {
+				token += "..."
+			}
+			lines = append(lines, ".TP", fmt.Sprintf(`\fB%s\fR`, roffEscape(token)), roffParagraph(pdef.def.getHelpString()))
+			if pdef.optional {
+				lines = append(lines, ".br", "Optional.")
+			}
+			if values := fixedValues(pdef.def); values != nil {
+				lines = append(lines, ".br", fmt.Sprintf("Choices: %s", roffEscape(strings.Join(values, ", "))))
+			}
+		}
+	}
+
+	if len(p.commandnames) > 0 {
+		lines = append(lines, ".SH COMMANDS")
+		for _, verb := range p.commandnames {
+			lines = append(lines, ".TP", fmt.Sprintf(`\fB%s\fR`, roffEscape(verb)))
+			lines = append(lines, roffEscape(strings.SplitN(p.commands[verb].helptext, "\n", 2)[0]))
+		}
+	}
+
+	if len(p.post_helptext) > 0 {
+		lines = append(lines, ".SH NOTES", roffParagraph(p.post_helptext))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// the man page lines for a single flag
+func (p *Parser) manFlagLines(name string) []string {
+	def := p.definitions[name]
+	_, ismode := def.(def_Mode)
+
+	flags := []string{}
+	for _, long := range strings.Split(p.longFlagsLabel(name), ", ") {
+		flags = append(flags, roffFlag(long))
+	}
+	if !ismode {
+		for _, short := range p.shortFlagsFor(name) {
+			flags = append(flags, roffFlag(fmt.Sprintf("-%c", short)))
+		}
+	}
+	header := strings.Join(flags, ", ")
+	if _, ok := p.implicit_values[name]; ok {
+		header += fmt.Sprintf(`[=\fI%s\fR]`, roffEscape(valueTypeName(def)))
+	} else if takesValue(def) {
+		header += fmt.Sprintf(` \fI%s\fR`, roffEscape(valueTypeName(def)))
+	}
+
+	helpstr := def.getHelpString()
+	if ismode {
+		// the mode letters are listed separately
+		helpstr = helpSummary(def)
+	}
+	lines := []string{".TP", header, roffParagraph(helpstr)}
+
+	details := []string{}
+	if p.required[name] {
+		details = append(details, "Required.")
+	}
+	switch def.(type) {
+	case def_Count:
+		details = append(details, "Each appearance is counted.")
+	case def_Appender:
+		details = append(details, "Can be specified multiple times.")
+	}
+	if defval, ok := defaultString(def); ok {
+		details = append(details, fmt.Sprintf("Default: %s", roffEscape(defval)))
+	}
+	if choices, ok := def.(def_Choices); ok {
+		details = append(details, fmt.Sprintf("Choices: %s", roffEscape(strings.Join(choices.choices, ", "))))
+	}
+	if mode, ok := def.(def_Mode); ok {
+		modes := []string{}
+		for _, short := range p.shortFlagsFor(name) {
+			modes = append(modes, fmt.Sprintf("%s %s", roffFlag(fmt.Sprintf("-%c", short)), roffEscape(mode.modes[short])))
+		}
+		details = append(details, fmt.Sprintf("Modes: %s", strings.Join(modes, ", ")))
+	}
+	if implicit, ok := p.implicit_values[name]; ok {
+		details = append(details, fmt.Sprintf("Without value: %s", roffEscape(implicit)))
+	}
+	if envname := p.envName(name); envname != "" {
+		details = append(details, fmt.Sprintf("Environment: %s", roffEscape(envname)))
+	}
+
+	for _, detail := range details {
+		lines = append(lines, ".br", detail)
+	}
+	return lines
+}
@@ -0,0 +1,78 @@
+package goargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ManPage(t *testing.T) {
+	parser := NewParser("whack [OPTIONS] TARGET\nwhack --list")
+	parser.String("gopher", "gaffer", "Wee rat")
+	parser.SetShortFlag('g', "gopher")
+	parser.SetRequired("gopher")
+	parser.Count("hard", "How hard?")
+	parser.SetShortFlag('h', "hard")
+	parser.Choices("tool", []string{"rolling-pin", "hammer"}, "What to use?\nChoose wisely")
+	parser.Mode("damage", "blunt", map[rune]string{'b': "blunt", 's': "sharp"}, "Damage type")
+	parser.Appender("noise", `Acceptable \squeaks`)
+	parser.SetEnvVar("noise", "WHACK_NOISE")
+	parser.PositionalString("TARGET", "What to whack")
+	parser.SetPostHelptext(".Have fun")
+
+	manpage := parser.SPrintManPage("whack", 6, "whack the mole")
+	expect := strings.Join([]string{
+		`.TH WHACK 6`,
+		`.SH NAME`,
+		`whack \- whack the mole`,
+		`.SH SYNOPSIS`,
+		`.nf`,
+		`whack [OPTIONS] TARGET`,
+		`whack --list`,
+		`.fi`,
+		`.SH OPTIONS`,
+		`.TP`,
+		`\fB\-\-gopher\fR, \fB\-g\fR \fISTRING\fR`,
+		`Wee rat`,
+		`.br`,
+		`Required.`,
+		`.br`,
+		`Default: gaffer`,
+		`.TP`,
+		`\fB\-\-hard\fR, \fB\-h\fR`,
+		`How hard?`,
+		`.br`,
+		`Each appearance is counted.`,
+		`.TP`,
+		`\fB\-\-tool\fR \fISTRING\fR`,
+		`What to use?`,
+		`.br`,
+		`Choose wisely`,
+		`.br`,
+		`Default: rolling-pin`,
+		`.br`,
+		`Choices: rolling-pin, hammer`,
+		`.TP`,
+		`\fB\-\-damage\fR \fISTRING\fR`,
+		`Damage type`,
+		`.br`,
+		`Default: blunt`,
+		`.br`,
+		`Modes: \fB\-b\fR blunt, \fB\-s\fR sharp`,
+		`.TP`,
+		`\fB\-\-noise\fR \fISTRING\fR`,
+		`Acceptable \esqueaks`,
+		`.br`,
+		`Can be specified multiple times.`,
+		`.br`,
+		`Environment: WHACK_NOISE`,
+		`.SH ARGUMENTS`,
+		`.TP`,
+		`\fBTARGET\fR`,
+		`What to whack`,
+		`.SH NOTES`,
+		`\&.Have fun`,
+	}, "\n") + "\n"
+	if manpage != expect {
+		t.Errorf("Mismatched man page. Got:\n<<%s>>\nInstead of:\n<<%s>>", manpage, expect)
+	}
+}
@@ -1,11 +1,14 @@
 package goargs
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"slices"
 	"strings"
+	"unicode/utf8"
 )
 
 const _VALID_SFLAGS = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
@@ -14,6 +17,8 @@ type t_VarDef interface {
 	getName() string
 	assign(string) error
 	getHelpString() string
+	// restore the default value
+	reset()
 }
 
 // A discrete Parser to hold a number of argument definitions.
@@ -25,23 +30,90 @@ type Parser struct {
 	longnames        []string
 	helptext         string
 	post_helptext    string
+	program_name     string
 	require_flagdefs bool
-	// Non-flag tokens in the arguments
-	positionals []string
+	// Flags that must be supplied, by name, and bounds of the positionals count
+	required        map[string]bool
+	min_positionals int
+	max_positionals int
+	// Environment variables bound to flags, by flag name, and prefix for deriving them
+	envnames   map[string]string
+	env_prefix string
+	// Values loaded from configuration files, by flag name
+	config_values map[string][]string
+	// Non-flag tokens in the arguments, and their declared definitions
+	positionals     []string
+	positional_defs []t_Positional
 	// All tokens found after the first instance of `--`
 	passdown_args []string
+	// Sub-command parsers, by verb, and the verbs in declaration order
+	commands     map[string]*Parser
+	commandnames []string
+	// The sub-command selected during the last parse, if any
+	selected_name string
+	selected      *Parser
+	// Whether this parser handles a sub-command, rather than the program
+	is_command bool
+	// Completion functions for flag and positional values, by name
+	completers        map[string]func(string) []string
+	completion_output io.Writer
+	// How SPrintHelp() lays out the help, and the width to wrap it to
+	help_layout HelpLayout
+	help_width  int
+	// Help groups of flags, by flag name, and the group names in order of first use
+	groups     map[string]string
+	groupnames []string
+	// Flags omitted from help, deprecated flags, and the warnings recorded during the last parse
+	hidden         map[string]bool
+	deprecations   map[string]t_Deprecation
+	warnings       []string
+	warning_output io.Writer
+	// Constraints across flags, in declaration order
+	constraints []t_Constraint
+	// How flags were set during parsing, by flag name
+	flag_states map[string]*t_FlagState
+	// Whether successive parses accumulate, rather than start from the defaults
+	accumulate bool
+	// The flags set since the last Reset(), which are not assigned again from the environment or configuration
+	set_flags map[string]bool
+	// Whether long flags can be abbreviated to unambiguous prefixes
+	allow_abbreviations bool
+	// Maximum edit distance of suggestions in errors
+	suggestion_distance int
+	// Values of flags appearing without a value, by flag name
+	implicit_values map[string]string
+	// Whether Bool flags can be set to false with `--no-NAME`
+	negatable_bools bool
+	// Alternative long names of flags, by alias, and the aliases in the order they were added
+	aliases    map[string]string
+	aliasnames []string
 }
 
 /*
 Create a new parser instance, with initial help text.
 Help text is printed before the flags' individual help strings are printed.
+A usage line generated from the definitions can precede it, see `SetProgramName()`.
 */
 func NewParser(helptext string) Parser {
 	var p Parser
 	p.definitions = make(map[string]t_VarDef)
 	p.shortnames = make(map[rune]t_VarDef)
+	p.envnames = make(map[string]string)
+	p.config_values = make(map[string][]string)
+	p.commands = make(map[string]*Parser)
+	p.completers = make(map[string]func(string) []string)
+	p.groups = make(map[string]string)
+	p.hidden = make(map[string]bool)
+	p.deprecations = make(map[string]t_Deprecation)
+	p.aliases = make(map[string]string)
+	p.implicit_values = make(map[string]string)
+	p.set_flags = make(map[string]bool)
+	p.flag_states = make(map[string]*t_FlagState)
 	p.helptext = helptext
 	p.require_flagdefs = true
+	p.required = make(map[string]bool)
+	p.max_positionals = -1
+	p.suggestion_distance = _SUGGESTION_DISTANCE
 	return p
 }
 
@@ -51,17 +123,82 @@ func (p *Parser) RequireFlagDefs(require bool) {
 	p.require_flagdefs = require
 }
 
-// register a flag in the parser
-func (p *Parser) enqueueName(name string) {
-	if slices.Contains(p.longnames, name) {
-		panic(fmt.Sprintf("Flag '--%s' already defined.", name))
+/*
+Determine whether every Bool flag can also be set to false as `--no-NAME`. Off by default.
+When enabled, a bare `--NAME` sets true, even if the default is true, instead of toggling the default.
+Help then lists Bool flags as `--[no-]NAME`. Sub-commands added afterwards inherit the setting.
+*/
+func (p *Parser) NegatableBools(negatable bool) {
+	p.negatable_bools = negatable
+}
+
+/*
+Mark existing flags as required. Parse() returns a *MissingFlagsError listing all required flags
+that were not supplied, either on the command line, from an environment variable, or from configuration.
+Panics if a long flag is not yet registered.
+*/
+func (p *Parser) SetRequired(longnames ...string) {
+	for _, name := range longnames {
+		if _, ok := p.definitions[name]; !ok {
+			panic(fmt.Sprintf("Flag '--%s' not yet defined", name))
+		}
+		p.required[name] = true
+	}
+}
+
+/*
+Give an existing flag an implicit value, used when the flag appears without a value attached,
+as `--name` or `-n` rather than `--name=VALUE` or `-nVALUE`. The next token is then never taken as its value.
+e.g. `--color` meaning "auto", versus `--color=always`. Help lists the flag as `--name[=TYPE]`.
+Switch-like short flags also take the implicit value, so that `-v` sets a Count flag like `--verbose` does.
+Panics if a long flag is not yet registered.
+*/
+func (p *Parser) SetImplicitValue(longname string, value string) {
+	if _, ok := p.definitions[longname]; !ok {
+		panic(fmt.Sprintf("Flag '--%s' not yet defined", longname))
+	}
+	p.implicit_values[longname] = value
+}
+
+/*
+Declare the minimum and maximum number of positional arguments. A negative `max` means no maximum.
+Parse() returns a *PositionalCountError if the number of positionals found is out of bounds.
+*/
+func (p *Parser) SetPositionalCount(min int, max int) {
+	p.min_positionals = min
+	p.max_positionals = max
+}
+
+// check that a flag name is valid and not yet registered
+func (p *Parser) checkName(name string) error {
+	if _, ok := p.aliases[name]; ok || slices.Contains(p.longnames, name) {
+		return fmt.Errorf("Flag '--%s' already defined.", name)
 	}
 	if matched, _ := regexp.MatchString("^[a-zA-Z][a-zA-Z0-9_-]+$", name); !matched {
-		panic(fmt.Sprintf("Invalid flag name '%s'. Must be minimum two characters long and start with letter", name))
+		return fmt.Errorf("Invalid flag name '%s'. Must be minimum two characters long and start with letter", name)
+	}
+	return nil
+}
+
+// register a flag in the parser
+func (p *Parser) enqueueName(name string) {
+	if err := p.checkName(name); err != nil {
+		panic(err.Error())
 	}
 	p.longnames = append(p.longnames, name)
 }
 
+// check that a short flag rune is valid and not yet registered
+func (p *Parser) checkShortFlag(short rune) error {
+	if !strings.ContainsRune(_VALID_SFLAGS, short) {
+		return fmt.Errorf("Internal error: cannot use rune %c", short)
+	}
+	if gotname, ok := p.shortnames[short]; ok {
+		return fmt.Errorf("'-%c' already defined against '%s'", short, gotname.getName())
+	}
+	return nil
+}
+
 /*
 Set a single-character notation for an existing long flag.
 Panics if the code attempts to set a short flag rune that already exists,
@@ -69,11 +206,8 @@ or if the long flag is not yet registered,
 or if the rune value is not alpha-numeric.
 */
 func (p *Parser) SetShortFlag(short rune, longname string) {
-	if !strings.ContainsRune(_VALID_SFLAGS, short) {
-		panic(fmt.Sprintf("Internal error: cannot use rune %c", short))
-	}
-	if gotname, ok := p.shortnames[short]; ok {
-		panic(fmt.Sprintf("'-%c' already defined against '%s'", short, gotname))
+	if err := p.checkShortFlag(short); err != nil {
+		panic(err.Error())
 	}
 	def, ok := p.definitions[longname]
 	if !ok {
@@ -118,21 +252,170 @@ func (p *Parser) UnpackArgs(idx int, ref ...interface{}) ([]string, error) {
 	return remains, nil
 }
 
+// Classify an error from assigning `value` to the flag `name`.
+// Choice errors are already classified, and receive suggestions; all others are invalid values.
+func (p *Parser) valueError(name string, value string, err error) error {
+	var choice_err *InvalidChoiceError
+	if errors.As(err, &choice_err) {
+		return p.suggestChoices(err)
+	}
+	return &InvalidValueError{name, value, err}
+}
+
 func (p *Parser) clearParsedData() {
 	p.positionals = []string{}
 	p.passdown_args = []string{}
 }
 
+/*
+Restore every flag and positional to its default value, including those of sub-commands,
+and clear the positional and passdown arguments, the selected sub-command and the warnings.
+Configuration values loaded with ParseConfig() are kept.
+*/
+func (p *Parser) Reset() {
+	for _, def := range p.definitions {
+		def.reset()
+	}
+	for _, pdef := range p.positional_defs {
+		pdef.def.reset()
+	}
+	p.clearParsedData()
+	p.set_flags = make(map[string]bool)
+	p.flag_states = make(map[string]*t_FlagState)
+	p.selected_name = ""
+	p.selected = nil
+	p.warnings = nil
+	for _, sub := range p.commands {
+		sub.Reset()
+	}
+}
+
+/*
+Determine whether successive calls to Parse() accumulate onto the results of previous ones. Off by default:
+each Parse() starts from a clean slate, see Reset(). When on, positionals are appended to those found previously,
+Count and Appender flags carry on from their current values, and flags not found keep their current value.
+Flags set by previous parses are not assigned again from the environment or configuration.
+Sub-commands added afterwards inherit the setting.
+*/
+func (p *Parser) SetAccumulate(accumulate bool) {
+	p.accumulate = accumulate
+}
+
+// A flag found in a short flag token
+type t_ShortFlag struct {
+	flag rune
+	def  t_VarDef
+}
+
+// activate a switch-like short flag
+func (self t_ShortFlag) activate(negatable bool) {
+	switch self.def.(type) {
+	case def_Bool:
+		self.def.(def_Bool).activate(negatable)
+	case def_Count:
+		self.def.(def_Count).increment()
+	case def_Mode:
+		self.def.(def_Mode).setShortMode(self.flag)
+	}
+}
+
+/*
+Resolve a short flag token like `-vaN` into its definitions, without activating them.
+Switch-like flags (Bool, Count, Mode) can be combined. As with getopt, the first value-taking flag
+ends the cluster, and is returned as `valdef`: the rest of the token is its `value` (as in `-n5`
+or `-vaNRoo`), or if it is last in the token, `value` is nil and the value is the next token.
+If a flag is not defined, returns an error if flag definitions are required, else `known` is false.
+The switches preceding an undefined flag are returned in all cases.
+*/
+func (p *Parser) resolveShortFlags(token string) (switches []t_ShortFlag, valdef t_VarDef, value *string, known bool, err error) {
+	for i, sflag := range token[1:] {
+		def, found_sflag := p.shortnames[sflag]
+		if !found_sflag && p.require_flagdefs {
+			return switches, nil, nil, false, &UnknownFlagError{string(sflag), true, p.shortSuggestions(sflag)}
+		} else if !found_sflag {
+			return switches, nil, nil, false, nil
+		}
+		switch def.(type) {
+		case def_Bool, def_Count, def_Mode:
+			switches = append(switches, t_ShortFlag{sflag, def})
+		default:
+			if rest := token[1+i+utf8.RuneLen(sflag):]; rest != "" {
+				value = &rest
+			}
+			return switches, def, value, true, nil
+		}
+	}
+	return switches, nil, nil, true, nil
+}
+
+// the Bool definition negated by a `no-NAME` long flag, if negation is enabled
+func (p *Parser) negatedBool(longname string) (t_VarDef, bool) {
+	name, found := strings.CutPrefix(longname, "no-")
+	if !p.negatable_bools || !found {
+		return nil, false
+	}
+	def, ok := p.lookupFlag(name)
+	if _, isbool := def.(def_Bool); !ok || !isbool {
+		return nil, false
+	}
+	return def, true
+}
+
 /*
 Parse custom token sequence.
 
+* Each call starts from the flags' default values, unless `SetAccumulate(true)` was used, see `Reset()`
 * If flag definitions are required (default), returns an error for unrecognised flags
 * Else, unrecognised flags are stored unparsed in the positional arguments
 * See `RequireFlagDefs(bool)`
+* Errors can be classified with `errors.As()`, see the error types in this package
+* Flags with an implicit value do not take the next token as their value, see `SetImplicitValue()`
+* Count flags accept an explicit count as `--NAME=N`
+* Long flags can be abbreviated to unambiguous prefixes if enabled with `AllowAbbreviations()`
+* Bool flags accept an explicit value as `--NAME=VALUE`, with values true/false, yes/no or 1/0,
+  and `--no-NAME` if enabled with `NegatableBools()`
+* Flags not found in the tokens take their value from their environment variable, if bound,
+  else from loaded configuration values, if any (see `ParseConfig()`)
+* Returns an error if required flags are missing, or if the number of positionals is out of bounds
+* Returns an error if flags were supplied against constraints, see `SetMutuallyExclusive()` etc.
+  Constrained flags from the command line override the others' environment and configuration values.
+* Positional tokens are assigned to the declared positionals, if any (see `PositionalString()` etc)
+* If sub-commands are registered, the first positional token matching a verb selects that
+  sub-command, and all subsequent tokens are parsed by the sub-command's parser instead
+* Which flags were set, and from which tokens, is recorded, see `Changed()` and `Visit()`
+* Uses of deprecated flags are recorded as warnings, see `SetDeprecated()` and `Warnings()`
+* If the first token is COMPLETE_TOKEN, prints completion candidates and returns ErrCompletion (see `Complete()` and `SetCompletionOutput()`)
 */
 func (p *Parser) Parse(args []string) error {
+	if len(args) > 0 && args[0] == COMPLETE_TOKEN && !p.is_command {
+		out := p.completion_output
+		if out == nil {
+			out = os.Stdout
+		}
+		for _, candidate := range p.Complete(args[1:]) {
+			fmt.Fprintln(out, candidate)
+		}
+		return ErrCompletion
+	}
+	return p.parse(args, 0)
+}
+
+// Parse tokens that start at index `offset` of the tokens given to the top-level Parse()
+func (p *Parser) parse(args []string, offset int) error {
+	if p.accumulate {
+		p.selected_name = ""
+		p.selected = nil
+		p.warnings = nil
+	} else {
+		p.Reset()
+	}
+	var subargs []string
+
+	tokens := args
 	args, passdowns := splitTokensBefore("--", args)
 	p.passdown_args = passdowns
+	// when accumulating, flags set by previous parses count as already set
+	seen := p.set_flags
 
 	// CONFESSION : I don't like that this function is so convoluted.
 
@@ -149,55 +432,66 @@ func (p *Parser) Parse(args []string) error {
 				longname = seq[0]
 				nextVal = &seq[1]
 			}
-			def_ifc = p.definitions[longname]
+			def, negated, err := p.resolveLongFlag(longname)
+			if err != nil {
+				return err
+			}
+			def_ifc = def
+			if negated {
+				if nextVal != nil {
+					return &InvalidValueError{longname, *nextVal, fmt.Errorf("--%s does not take a value", longname)}
+				}
+				nextVal = new(string)
+				*nextVal = "false"
+			}
 
 			if def_ifc == nil && p.require_flagdefs {
-				return fmt.Errorf("unknown flag %s", token)
+				return &UnknownFlagError{longname, false, p.longSuggestions(longname)}
 			}
 
 		} else if len(token) > 1 && token[:1] == "-" {
 			// Typically do not retain short flag aggregates
 			// However if short flag is not found, retain the lot
-			retain_token = false
-			for _, sflag := range token[1:] {
-				def, found_sflag := p.shortnames[sflag]
-				if !found_sflag && p.require_flagdefs {
-					return fmt.Errorf("unknown short flag '%c'", sflag)
-				} else if !found_sflag {
-					retain_token = true
-					break
-				}
-				switch def.(type) {
-				case def_Bool:
-					def.(def_Bool).activate()
-					continue
-				case def_Count:
-					def.(def_Count).increment()
-					continue
-				case def_Mode:
-					def.(def_Mode).setShortMode(sflag)
-					continue
-				default:
-					if len(token) == 2 {
-						def_ifc = def
-					} else {
-						return fmt.Errorf("please specify '%c' on its own as '-%c VALUE'", sflag, sflag)
-					}
+			switches, valdef, value, known, err := p.resolveShortFlags(token)
+			for _, sf := range switches {
+				name, err := p.activateShortFlag(sf)
+				if err != nil {
+					return err
 				}
+				p.markSet(seen, name, SOURCE_CLI, offset+i)
 			}
+			if err != nil {
+				return err
+			}
+			retain_token = !known
+			def_ifc = valdef
+			nextVal = value
 		}
 
 		if def_ifc != nil {
+			def_ifc = p.deprecationTarget(def_ifc)
+			p.markSet(seen, def_ifc.getName(), SOURCE_CLI, offset+i)
+			if implicit, ok := p.implicit_values[def_ifc.getName()]; ok && nextVal == nil {
+				nextVal = &implicit
+			}
 			switch def_ifc.(type) {
 			case def_Bool:
-				def_ifc.(def_Bool).activate()
+				if nextVal == nil {
+					def_ifc.(def_Bool).activate(p.negatable_bools)
+				} else if err := def_ifc.assign(*nextVal); err != nil {
+					return p.valueError(def_ifc.getName(), *nextVal, err)
+				}
 			case def_Count:
-				def_ifc.(def_Count).increment()
+				if nextVal == nil {
+					def_ifc.(def_Count).increment()
+				} else if err := def_ifc.assign(*nextVal); err != nil {
+					return p.valueError(def_ifc.getName(), *nextVal, err)
+				}
 			default:
 				if nextVal == nil {
 					i++
 					if i >= len(args) {
-						return fmt.Errorf("expected value after %s", token)
+						return &MissingValueError{def_ifc.getName(), token}
 					}
 					nextVal = &args[i]
 				}
@@ -206,18 +500,64 @@ func (p *Parser) Parse(args []string) error {
 				//     def_ifc.(FuncDef).call(*nextVal)
 				default:
 					if err := def_ifc.assign(*nextVal); err != nil {
-						return err
+						return p.valueError(def_ifc.getName(), *nextVal, err)
 					}
 				}
 			}
 
-		} else {
-			if retain_token {
-				p.positionals = append(p.positionals, token)
+		} else if retain_token {
+			if sub, ok := p.commands[token]; ok && len(p.positionals) == 0 {
+				// `args` is a prefix of `tokens`, so the remainder still holds any `--` passdowns
+				p.selected_name = token
+				p.selected = sub
+				p.passdown_args = []string{}
+				subargs = tokens[i+1:]
+				break
 			}
+			p.positionals = append(p.positionals, token)
+		}
+	}
+
+	overridden := p.overriddenByCLI(seen)
+	if err := p.applyEnv(seen, overridden); err != nil {
+		return err
+	}
+	if err := p.applyConfig(seen, overridden); err != nil {
+		return err
+	}
+	if err := p.checkRequirements(seen); err != nil {
+		return err
+	}
+	if err := p.checkConstraints(seen); err != nil {
+		return err
+	}
+	if err := p.assignPositionals(); err != nil {
+		return err
+	}
+
+	if p.selected != nil {
+		return p.selected.parse(subargs, offset+len(tokens)-len(subargs))
+	}
+
+	return nil
+}
+
+// check required flags were seen, and the number of positionals is within bounds
+func (p *Parser) checkRequirements(seen map[string]bool) error {
+	missing := []string{}
+	for _, name := range p.longnames {
+		if p.required[name] && !seen[name] {
+			missing = append(missing, name)
 		}
 	}
+	if len(missing) > 0 {
+		return &MissingFlagsError{missing}
+	}
 
+	count := len(p.positionals)
+	if count < p.min_positionals || (p.max_positionals >= 0 && count > p.max_positionals) {
+		return &PositionalCountError{p.min_positionals, p.max_positionals, count}
+	}
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package goargs
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/taikedz/gocheck"
@@ -88,12 +90,46 @@ func Test_ParseArgs_Shortflags(t *testing.T) {
 	gocheck.Equal(t, "Rae", *name)
 	gocheck.EqualArr(t, queue, []string{"one", "two"})
 
-	if err := parser.Parse([]string{"-vavN", "Roo"}); err == nil {
-		t.Errorf("Shortflags parse with combined vavN should have failed, value of name is '%s'", *name)
+	if err := parser.Parse([]string{"-vavN", "Roo"}); err != nil {
+		t.Errorf("Failed shortflags parse with value-taking flag last: %v", err)
 	}
-	gocheck.Equal(t, 4, *verbose)
+	gocheck.Equal(t, 2, *verbose)
 	gocheck.Equal(t, true, *admit)
-	gocheck.Equal(t, "Rae", *name)
+	gocheck.Equal(t, "Roo", *name)
+
+	if err := parser.Parse([]string{"-NRay", "-vQthree", "-QvN"}); err != nil {
+		t.Errorf("Failed shortflags parse with attached values: %v", err)
+	}
+	gocheck.Equal(t, 1, *verbose)
+	gocheck.Equal(t, "Ray", *name)
+	gocheck.EqualArr(t, queue, []string{"one", "two", "three", "vN"})
+
+	var missing *MissingValueError
+	if err := parser.Parse([]string{"-vN"}); !errors.As(err, &missing) {
+		t.Errorf("Shortflags parse without value should have failed, got: %v", err)
+	}
+}
+
+func Test_ParseArgs_ShortflagValues(t *testing.T) {
+	parser := NewParser("")
+	count := parser.Int("count", 1, "")
+	parser.SetShortFlag('n', "count")
+	tool := parser.Choices("tool", []string{"hammer", "mallet"}, "")
+	parser.SetShortFlag('t', "tool")
+	noise := parser.Appender("noise", "")
+	parser.SetShortFlag('s', "noise")
+
+	if err := parser.Parse([]string{"-n5", "-tmallet", "-sbonk", "-s", "squeak"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, 5, *count)
+	gocheck.Equal(t, "mallet", *tool)
+	gocheck.EqualArr(t, []string{"bonk", "squeak"}, *noise)
+
+	var choiceerr *InvalidChoiceError
+	if err := parser.Parse([]string{"-tspanner"}); !errors.As(err, &choiceerr) {
+		t.Errorf("Attached value should be validated, got: %v", err)
+	}
 }
 
 func Test_ParseArgs_Good(t *testing.T) {
@@ -192,3 +228,271 @@ func Test_ParseArgs_Multishortflags(t *testing.T) {
 
 	gocheck.EqualArr(t, []string{"hi", "bye"}, parser.Args())
 }
+
+func Test_ParseArgs_Required(t *testing.T) {
+	parser := NewParser("")
+	parser.String("user", "", "help")
+	parser.String("token", "", "help")
+	parser.Int("port", 80, "help")
+	parser.SetRequired("user", "token")
+
+	err := parser.Parse([]string{"--port", "8080"})
+	var missing *MissingFlagsError
+	if !errors.As(err, &missing) {
+		t.Errorf("Should have failed with missing flags, got: %v", err)
+		return
+	}
+	gocheck.EqualArr(t, []string{"user", "token"}, missing.Flags)
+
+	if err := parser.Parse([]string{"--user", "alex", "--token", "x"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+}
+
+func Test_ParseArgs_PositionalCount(t *testing.T) {
+	parser := NewParser("")
+	parser.SetPositionalCount(1, 2)
+
+	var counterr *PositionalCountError
+	if err := parser.Parse([]string{}); !errors.As(err, &counterr) {
+		t.Errorf("Should have failed with too few positionals, got: %v", err)
+	}
+	parser.clearParsedData()
+
+	if err := parser.Parse([]string{"a", "b", "c"}); !errors.As(err, &counterr) {
+		t.Errorf("Should have failed with too many positionals, got: %v", err)
+	} else {
+		gocheck.Equal(t, 3, counterr.Got)
+	}
+	parser.clearParsedData()
+
+	if err := parser.Parse([]string{"a", "b"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+}
+
+func Test_ParseArgs_BoolValues(t *testing.T) {
+	parser := NewParser("")
+	admit := parser.Bool("admit", false, "")
+	cache := parser.Bool("cache", true, "")
+
+	if err := parser.Parse([]string{"--admit=yes", "--cache=false"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, true, *admit)
+	gocheck.Equal(t, false, *cache)
+
+	if err := parser.Parse([]string{"--admit=0", "--cache=1"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, false, *admit)
+	gocheck.Equal(t, true, *cache)
+
+	var invalid *InvalidValueError
+	if err := parser.Parse([]string{"--admit=maybe"}); !errors.As(err, &invalid) {
+		t.Errorf("Should have failed with an invalid value, got: %v", err)
+	}
+
+	var unknown *UnknownFlagError
+	if err := parser.Parse([]string{"--no-cache"}); !errors.As(err, &unknown) {
+		t.Errorf("Negation should be off by default, got: %v", err)
+	}
+}
+
+func Test_ParseArgs_NegatableBools(t *testing.T) {
+	parser := NewParser("")
+	parser.NegatableBools(true)
+	cache := parser.Bool("cache", true, "Use the cache")
+	parser.SetAlias("caching", "cache")
+	parser.String("name", "", "")
+
+	if err := parser.Parse([]string{"--no-caching"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, false, *cache)
+
+	var invalid *InvalidValueError
+	if err := parser.Parse([]string{"--no-cache=true"}); !errors.As(err, &invalid) {
+		t.Errorf("Negated flag should not take a value, got: %v", err)
+	}
+	var unknown *UnknownFlagError
+	if err := parser.Parse([]string{"--no-name"}); !errors.As(err, &unknown) {
+		t.Errorf("Only Bool flags should be negatable, got: %v", err)
+	}
+
+	gocheck.EqualArr(t, []string{
+		"",
+		"",
+		"  --[no-]cache, --[no-]caching",
+		"    default: true",
+		"    Use the cache",
+	}, strings.Split(parser.SPrintHelp(), "\n")[:5])
+	gocheck.EqualArr(t, []string{"--no-cache", "--no-caching", "--name"}, parser.Complete([]string{"--n"}))
+}
+
+func Test_ParseArgs_NegatableBools_Switch(t *testing.T) {
+	parser := NewParser("")
+	parser.NegatableBools(true)
+	cache := parser.Bool("cache", true, "Use the cache")
+	parser.SetAlias("caching", "cache")
+	parser.SetShortFlag('c', "cache")
+	verbose := parser.Bool("verbose", false, "Print more")
+
+	// a bare flag always sets true, even against a true default
+	for _, args := range [][]string{{"--cache"}, {"--no-cache", "--caching"}, {"-c"}, {"--verbose"}} {
+		if err := parser.Parse(args); err != nil {
+			t.Errorf("Failed parse: %v", err)
+		}
+		gocheck.Equal(t, true, *cache)
+	}
+	gocheck.Equal(t, true, *verbose)
+}
+
+func Test_ParseArgs_ImplicitValue(t *testing.T) {
+	parser := NewParser("")
+	color := parser.Choices("color", []string{"never", "auto", "always"}, "")
+	parser.SetShortFlag('c', "color")
+	parser.SetImplicitValue("color", "auto")
+	width := parser.Int("width", 80, "")
+	parser.SetImplicitValue("width", "100")
+	verbose := parser.Count("verbose", "")
+	parser.SetShortFlag('v', "verbose")
+
+	if err := parser.Parse([]string{"--color", "always", "--width"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "auto", *color)
+	gocheck.Equal(t, 100, *width)
+	gocheck.EqualArr(t, []string{"always"}, parser.Args())
+	parser.clearParsedData()
+
+	if err := parser.Parse([]string{"--color=always", "--width=120", "-vc", "x"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "auto", *color)
+	gocheck.Equal(t, 120, *width)
+	gocheck.Equal(t, 1, *verbose)
+	gocheck.EqualArr(t, []string{"x"}, parser.Args())
+	parser.clearParsedData()
+
+	if err := parser.Parse([]string{"-cnever", "--verbose=3", "-v"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "never", *color)
+	gocheck.Equal(t, 4, *verbose)
+
+	var invalid *InvalidValueError
+	if err := parser.Parse([]string{"--verbose=lots"}); !errors.As(err, &invalid) {
+		t.Errorf("Should have failed with an invalid count, got: %v", err)
+	}
+}
+
+func Test_ParseArgs_ImplicitValue_Switch(t *testing.T) {
+	parser := NewParser("")
+	verbose := parser.Count("verbose", "")
+	parser.SetShortFlag('v', "verbose")
+	parser.SetImplicitValue("verbose", "3")
+	quiet := parser.Bool("quiet", true, "")
+	parser.SetShortFlag('q', "quiet")
+	parser.SetImplicitValue("quiet", "true")
+
+	// short switches take the implicit value, like their long flags
+	for _, args := range [][]string{{"-v", "-q"}, {"-vq"}, {"--verbose", "--quiet"}} {
+		if err := parser.Parse(args); err != nil {
+			t.Errorf("Failed parse: %v", err)
+		}
+		gocheck.Equal(t, 3, *verbose)
+		gocheck.Equal(t, true, *quiet)
+	}
+}
+
+func Test_ParseArgs_Fresh(t *testing.T) {
+	parser := NewParser("")
+	name := parser.String("name", "who", "")
+	verbose := parser.Count("verbose", "")
+	noise := parser.Appender("noise", "")
+	file := parser.PositionalString("FILE", "")
+
+	if err := parser.Parse([]string{"--name", "Rae", "--verbose", "--noise", "bonk", "a.txt", "--", "x"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	if err := parser.Parse([]string{"--verbose", "--noise", "squeak", "b.txt"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "who", *name)
+	gocheck.Equal(t, 1, *verbose)
+	gocheck.EqualArr(t, []string{"squeak"}, *noise)
+	gocheck.Equal(t, "b.txt", *file)
+	gocheck.EqualArr(t, []string{"b.txt"}, parser.Args())
+	gocheck.Equal(t, 0, len(parser.ExtraArgs()))
+}
+
+func Test_ParseArgs_Accumulate(t *testing.T) {
+	parser := NewParser("")
+	parser.SetAccumulate(true)
+	name := parser.String("name", "who", "")
+	verbose := parser.Count("verbose", "")
+	noise := parser.Appender("noise", "")
+	files := parser.PositionalRest("FILES", "")
+
+	if err := parser.Parse([]string{"--name", "Rae", "--verbose", "--noise", "bonk", "a.txt"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	if err := parser.Parse([]string{"--verbose", "--noise", "squeak", "b.txt"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, "Rae", *name)
+	gocheck.Equal(t, 2, *verbose)
+	gocheck.EqualArr(t, []string{"bonk", "squeak"}, *noise)
+	gocheck.EqualArr(t, []string{"a.txt", "b.txt"}, parser.Args())
+	gocheck.EqualArr(t, []string{"a.txt", "b.txt"}, *files)
+
+	parser.Reset()
+	gocheck.Equal(t, "who", *name)
+	gocheck.Equal(t, 0, *verbose)
+	gocheck.Equal(t, 0, len(*noise))
+	gocheck.Equal(t, 0, len(parser.Args()))
+}
+
+func Test_ParseArgs_Accumulate_Env(t *testing.T) {
+	t.Setenv("X_TAG", "e")
+	t.Setenv("X_NAME", "Env")
+
+	parser := NewParser("")
+	parser.SetAccumulate(true)
+	tags := parser.Appender("tag", "")
+	parser.SetEnvVar("tag", "X_TAG")
+	name := parser.String("name", "who", "")
+	parser.SetEnvVar("name", "X_NAME")
+	labels := parser.Appender("label", "")
+	if err := parser.ParseConfig(strings.NewReader("label = c"), CONFIG_INI); err != nil {
+		t.Errorf("Failed config: %v", err)
+	}
+
+	if err := parser.Parse([]string{"--name", "Rae"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	if err := parser.Parse([]string{}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	// the environment and configuration are not applied again to flags already set
+	gocheck.EqualArr(t, []string{"e"}, *tags)
+	gocheck.EqualArr(t, []string{"c"}, *labels)
+	gocheck.Equal(t, "Rae", *name)
+}
+
+func Test_Reset_Commands(t *testing.T) {
+	parser := NewParser("")
+	db := parser.AddCommand("db", "")
+	dry := db.Bool("dry-run", false, "")
+
+	if err := parser.Parse([]string{"db", "--dry-run"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, true, *dry)
+	gocheck.EqualArr(t, []string{"db"}, parser.CommandPath())
+
+	parser.Reset()
+	gocheck.Equal(t, false, *dry)
+	gocheck.Equal(t, 0, len(parser.CommandPath()))
+}
@@ -0,0 +1,218 @@
+package goargs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type t_Positional struct {
+	def      t_VarDef
+	optional bool
+	variadic bool
+}
+
+// register a positional argument definition in the parser
+func (p *Parser) enqueuePositional(def t_VarDef, variadic bool) {
+	name := def.getName()
+	if matched, _ := regexp.MatchString("^[a-zA-Z][a-zA-Z0-9_-]*$", name); !matched {
+		panic(fmt.Sprintf("Invalid positional name '%s'. Must start with letter", name))
+	}
+	for _, pdef := range p.positional_defs {
+		if pdef.def.getName() == name {
+			panic(fmt.Sprintf("Positional '%s' already defined.", name))
+		}
+		if pdef.variadic {
+			panic(fmt.Sprintf("Cannot define positional '%s' after variadic '%s'", name, pdef.def.getName()))
+		}
+		if pdef.optional {
+			panic(fmt.Sprintf("Cannot define required positional '%s' after optional '%s'", name, pdef.def.getName()))
+		}
+	}
+	p.positional_defs = append(p.positional_defs, t_Positional{def, false, variadic})
+}
+
+/*
+Mark positionals as optional. A missing optional positional keeps its default value.
+Panics if a positional is not yet registered, or if a required positional follows an optional one.
+*/
+func (p *Parser) SetPositionalOptional(names ...string) {
+	for _, name := range names {
+		found := false
+		for i := range p.positional_defs {
+			if p.positional_defs[i].def.getName() == name {
+				p.positional_defs[i].optional = true
+				found = true
+			} else if found && !p.positional_defs[i].optional && !p.positional_defs[i].variadic {
+				panic(fmt.Sprintf("Cannot make positional '%s' optional before required '%s'", name, p.positional_defs[i].def.getName()))
+			}
+		}
+		if !found {
+			panic(fmt.Sprintf("Positional '%s' not yet defined", name))
+		}
+	}
+}
+
+// Register a string positional, storing to the supplied `value *string` pointer
+// The current value of the variable is kept as default, if the positional is optional.
+func (p *Parser) PositionalStringVar(value *string, name string, helpstr string) {
+	p.enqueuePositional(def_String{name, *value, value, helpstr}, false)
+}
+
+// Register a string positional, storing to the returned `*string` pointer
+func (p *Parser) PositionalString(name string, helpstr string) *string {
+	var val string
+	p.PositionalStringVar(&val, name, helpstr)
+	return &val
+}
+
+// Register an int positional, storing to the supplied `value *int` pointer
+// The current value of the variable is kept as default, if the positional is optional.
+func (p *Parser) PositionalIntVar(value *int, name string, helpstr string) {
+	p.enqueuePositional(def_Int{name, *value, value, helpstr}, false)
+}
+
+// Register an int positional, storing to the returned `*int` pointer
+func (p *Parser) PositionalInt(name string, helpstr string) *int {
+	var val int
+	p.PositionalIntVar(&val, name, helpstr)
+	return &val
+}
+
+// Register a float positional, storing to the supplied `value *float32` pointer
+// The current value of the variable is kept as default, if the positional is optional.
+func (p *Parser) PositionalFloatVar(value *float32, name string, helpstr string) {
+	p.enqueuePositional(def_Float{name, *value, value, helpstr}, false)
+}
+
+// Register a float positional, storing to the returned `*float32` pointer
+func (p *Parser) PositionalFloat(name string, helpstr string) *float32 {
+	var val float32
+	p.PositionalFloatVar(&val, name, helpstr)
+	return &val
+}
+
+// Register a Choices positional, storing to the supplied `value *string` pointer
+// A Choices positional will only accept one of the specified `choices []string` elements,
+// and defaults to the first choice.
+func (p *Parser) PositionalChoicesVar(value *string, name string, choices []string, helpstr string) {
+	vdef := def_Choices{name, value, helpstr, choices}
+	*vdef.value = choices[0]
+	p.enqueuePositional(vdef, false)
+}
+
+// Register a Choices positional, storing to the returned `*string` pointer
+func (p *Parser) PositionalChoices(name string, choices []string, helpstr string) *string {
+	var val string
+	p.PositionalChoicesVar(&val, name, choices, helpstr)
+	return &val
+}
+
+// Register a variadic positional, appending all remaining positionals to the supplied `value *[]string` pointer
+// The variadic positional must be the last positional defined, and requires at least one value unless optional.
+func (p *Parser) PositionalRestVar(value *[]string, name string, helpstr string) {
+	p.enqueuePositional(def_Appender{name, slices.Clone(*value), value, helpstr}, true)
+}
+
+// Register a variadic positional, appending all remaining positionals to the returned `*[]string` pointer
+func (p *Parser) PositionalRest(name string, helpstr string) *[]string {
+	var val []string
+	p.PositionalRestVar(&val, name, helpstr)
+	return &val
+}
+
+// minimum and maximum (negative for unlimited) number of positionals accepted by the definitions
+func (p *Parser) positionalBounds() (int, int) {
+	lower := 0
+	for _, pdef := range p.positional_defs {
+		if !pdef.optional {
+			lower++
+		}
+		if pdef.variadic {
+			return lower, -1
+		}
+	}
+	return lower, len(p.positional_defs)
+}
+
+// assign the positional tokens to their definitions, if any are declared
+func (p *Parser) assignPositionals() error {
+	if len(p.positional_defs) == 0 {
+		return nil
+	}
+
+	count := len(p.positionals)
+	lower, upper := p.positionalBounds()
+	if count < lower || (upper >= 0 && count > upper) {
+		return &PositionalCountError{lower, upper, count}
+	}
+
+	for i, pdef := range p.positional_defs {
+		if i >= count {
+			break
+		}
+		tokens := p.positionals[i : i+1]
+		if pdef.variadic {
+			// all positionals found so far are assigned again, over the default
+			pdef.def.reset()
+			tokens = p.positionals[i:]
+		}
+		for _, token := range tokens {
+			if err := pdef.def.assign(token); err != nil {
+				return p.positionalError(pdef.def.getName(), token, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Classify an error from assigning `token` to the positional `name`
+func (p *Parser) positionalError(name string, token string, err error) error {
+	var choice_err *InvalidChoiceError
+	if errors.As(err, &choice_err) {
+		return p.suggestChoices(err)
+	}
+	return &InvalidValueError{"", token, fmt.Errorf("invalid value '%s' for %s: %v", token, name, err)}
+}
+
+// The positionals' usage notation, e.g. `NAME [AGE] FILES...`
+func (p *Parser) positionalUsage() string {
+	tokens := []string{}
+	for _, pdef := range p.positional_defs {
+		token := pdef.def.getName()
+		if pdef.variadic {
+			token += "..."
+		}
+		if pdef.optional {
+			token = "[" + token + "]"
+		}
+		tokens = append(tokens, token)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// help lines listing the positionals
+func (p *Parser) positionalHelpLines() []string {
+	if len(p.positional_defs) == 0 {
+		return []string{}
+	}
+
+	helplines := []string{"", fmt.Sprintf("Arguments: %s", p.positionalUsage())}
+	for _, pdef := range p.positional_defs {
+		name := pdef.def.getName()
+		if pdef.variadic {
+			name += "..."
+		}
+		helplines = append(helplines, fmt.Sprintf("  %s", name))
+		if pdef.optional {
+			helplines = append(helplines, "    (optional)")
+		}
+		if choices, ok := pdef.def.(def_Choices); ok {
+			helplines = append(helplines, fmt.Sprintf("    choices: %s", strings.Join(choices.choices, ", ")))
+		}
+		helplines = append(helplines, fmt.Sprintf("    %s", pdef.def.getHelpString()))
+	}
+	return helplines
+}
@@ -0,0 +1,115 @@
+package goargs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Positionals(t *testing.T) {
+	parser := NewParser("")
+	verbose := parser.Bool("verbose", false, "help")
+	name := parser.PositionalString("NAME", "Their name")
+	age := parser.PositionalInt("AGE", "Their age")
+	files := parser.PositionalRest("FILES", "Files to read")
+
+	if err := parser.Parse([]string{"Alex", "--verbose", "20", "one.txt", "two.txt"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+
+	gocheck.Equal(t, true, *verbose)
+	gocheck.Equal(t, "Alex", *name)
+	gocheck.Equal(t, 20, *age)
+	gocheck.EqualArr(t, []string{"one.txt", "two.txt"}, *files)
+	gocheck.EqualArr(t, []string{"Alex", "20", "one.txt", "two.txt"}, parser.Args())
+}
+
+func Test_Positionals_Optional(t *testing.T) {
+	parser := NewParser("")
+	action := parser.PositionalChoices("ACTION", []string{"start", "stop"}, "What to do")
+	delay := float32(1.5)
+	parser.PositionalFloatVar(&delay, "DELAY", "Seconds to wait")
+	parser.SetPositionalOptional("DELAY")
+
+	if err := parser.Parse([]string{"stop"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+	gocheck.Equal(t, "stop", *action)
+	gocheck.Equal(t, 1.5, delay)
+}
+
+func Test_Positionals_Fail(t *testing.T) {
+	parser := NewParser("")
+	parser.PositionalChoices("ACTION", []string{"start", "stop"}, "What to do")
+	parser.PositionalInt("COUNT", "How many")
+
+	var counterr *PositionalCountError
+	if err := parser.Parse([]string{"start"}); !errors.As(err, &counterr) {
+		t.Errorf("Expected PositionalCountError, got: %v", err)
+	} else {
+		gocheck.Equal(t, 2, counterr.Min)
+		gocheck.Equal(t, 2, counterr.Max)
+	}
+	parser.clearParsedData()
+
+	var choice *InvalidChoiceError
+	if err := parser.Parse([]string{"pause", "1"}); !errors.As(err, &choice) {
+		t.Errorf("Expected InvalidChoiceError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "ACTION", choice.Flag)
+	}
+	parser.clearParsedData()
+
+	var invalid *InvalidValueError
+	if err := parser.Parse([]string{"start", "many"}); !errors.As(err, &invalid) {
+		t.Errorf("Expected InvalidValueError, got: %v", err)
+	} else {
+		gocheck.Equal(t, "invalid value 'many' for COUNT: Could not parse many", err.Error())
+	}
+}
+
+func Test_Positionals_Help(t *testing.T) {
+	parser := NewParser("greet")
+	parser.PositionalChoices("GREETING", []string{"hello", "bye"}, "How to greet")
+	parser.PositionalRest("NAMES", "Who to greet")
+	parser.SetPositionalOptional("NAMES")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"greet",
+		"",
+		"",
+		"Arguments: GREETING [NAMES...]",
+		"  GREETING",
+		"    choices: hello, bye",
+		"    How to greet",
+		"  NAMES...",
+		"    (optional)",
+		"    Who to greet",
+	}, "\n")
+	if helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
+
+func Test_Positionals_Usage(t *testing.T) {
+	parser := NewParser("")
+	parser.Bool("verbose", false, "Print more")
+	parser.PositionalString("NAME", "Who to greet")
+	parser.PositionalRest("FILES", "Files to send")
+	parser.SetPositionalOptional("FILES")
+	gocheck.Equal(t, "Usage: greet [OPTIONS] NAME [FILES...]", parser.SPrintUsage("greet"))
+
+	tool := NewParser("Manage things")
+	tool.SetProgramName("tool")
+	db := tool.AddCommand("db", "")
+	db.PositionalString("TABLE", "Table to migrate")
+	gocheck.Equal(t, "Usage: tool COMMAND ...", tool.SPrintUsage("tool"))
+
+	gocheck.EqualArr(t, []string{"Usage: tool COMMAND ...", "", "Manage things", ""}, strings.Split(tool.SPrintHelp(), "\n")[:4])
+	gocheck.EqualArr(t, []string{"Usage: tool db TABLE", ""}, strings.Split(db.SPrintHelp(), "\n")[:2])
+}
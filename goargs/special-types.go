@@ -4,32 +4,40 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 )
 
 type def_Count struct {
 	name    string
+	defval  int
 	value   *int
 	helpstr string
 }
 
 func (self def_Count) getHelpString() string { return self.helpstr }
 func (self def_Count) getName() string       { return self.name }
+func (self def_Count) reset()                { *self.value = self.defval }
 func (self def_Count) assign(value string) error {
-	panic("(goargs) Invalid call to assign() on CountDef")
+	if val, err := strconv.Atoi(value); err != nil {
+		return fmt.Errorf("Could not parse %s", value)
+	} else {
+		*self.value = val
+	}
+	return nil
 }
 func (self def_Count) increment() { *self.value++ }
 
 // Register a Count flag, storing to the supplied `value *int` pointer
-// A Count flag increments by 1 every time the flag is seen.
+// A Count flag increments by 1 every time the flag is seen, or is set to N with `--name=N`.
 func (p *Parser) CountVar(value *int, name string, helpstr string) {
-	vdef := def_Count{name, value, helpstr}
+	vdef := def_Count{name, *value, value, helpstr}
 	p.definitions[name] = vdef
 	p.enqueueName(name)
 }
 
 // Register a Count flag, storing to the returned `*int` pointer
-// A Count flag increments by 1 every time the flag is seen.
+// A Count flag increments by 1 every time the flag is seen, or is set to N with `--name=N`.
 func (p *Parser) Count(name string, helpstr string) *int {
 	var val int = 0
 	p.CountVar(&val, name, helpstr)
@@ -47,9 +55,10 @@ type def_Choices struct {
 
 func (self def_Choices) getHelpString() string { return self.helpstr }
 func (self def_Choices) getName() string       { return self.name }
+func (self def_Choices) reset()                { *self.value = self.choices[0] }
 func (self def_Choices) assign(value string) error {
 	if !slices.Contains(self.choices, value) {
-		return fmt.Errorf("Invalid choice '%s'. Valid choices: %v", value, self.choices)
+		return &InvalidChoiceError{self.name, value, self.choices, false, nil}
 	}
 	*self.value = value
 	return nil
@@ -76,12 +85,14 @@ func (p *Parser) Choices(name string, choices []string, helpstr string) *string
 
 type def_Appender struct {
 	name    string
+	defval  []string
 	value   *[]string
 	helpstr string
 }
 
 func (self def_Appender) getHelpString() string { return self.helpstr }
 func (self def_Appender) getName() string       { return self.name }
+func (self def_Appender) reset()                { *self.value = slices.Clone(self.defval) }
 func (self def_Appender) assign(value string) error {
 	*self.value = append(*self.value, value)
 	return nil
@@ -90,7 +101,7 @@ func (self def_Appender) assign(value string) error {
 // Register an Appender flag, storing to the supplied `value *[]string` pointer
 // An Appender flag will append the associated value into the specified slice
 func (p *Parser) AppenderVar(value *[]string, name string, helpstr string) {
-	vdef := def_Appender{name, value, helpstr}
+	vdef := def_Appender{name, slices.Clone(*value), value, helpstr}
 	p.definitions[name] = vdef
 	p.enqueueName(name)
 }
@@ -114,6 +125,7 @@ type def_Func struct {
 func (self def_Func) getHelpString() string     { return self.helpstr }
 func (self def_Func) getName() string           { return self.name }
 func (self def_Func) assign(value string) error { return self.innerfunc(value) }
+func (self def_Func) reset()                    {}
 
 // Register a Function flag
 // The function defined at `funcdef` will be called each time the flag is seen, and be called
@@ -136,6 +148,7 @@ type def_Mode struct {
 
 func (self def_Mode) getHelpString() string { return self.helpstr }
 func (self def_Mode) getName() string       { return self.name }
+func (self def_Mode) reset()                { *self.value = self.defval }
 func (self def_Mode) assign(value string) error {
 	// go through the modes map, and check that the mode value is found there
 	var values []string
@@ -146,7 +159,8 @@ func (self def_Mode) assign(value string) error {
 		}
 		values = append(values, okval)
 	}
-	return fmt.Errorf("Invalid mode '%s' - choose from: %s", value, strings.Join(values, ", "))
+	sort.Strings(values)
+	return &InvalidChoiceError{self.name, value, values, true, nil}
 }
 func (self def_Mode) setShortMode(short rune) {
 	*self.value = self.modes[short]
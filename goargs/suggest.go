@@ -0,0 +1,105 @@
+package goargs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// The default maximum edit distance of suggestions, see SetSuggestionDistance()
+const _SUGGESTION_DISTANCE = 2
+
+/*
+Set the maximum edit distance between an unknown flag or invalid choice and the names or values
+suggested in its error, as in "did you mean --verbose?". A distance of 0 disables suggestions.
+Sub-commands added afterwards inherit the distance.
+*/
+func (p *Parser) SetSuggestionDistance(distance int) {
+	p.suggestion_distance = distance
+}
+
+// the Levenshtein distance between two strings, counted in runes
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// the candidates within `distance` edits of `word`, closest first, else in the order given
+func suggestions(word string, candidates []string, distance int) []string {
+	found := []string{}
+	distances := map[string]int{}
+	for _, candidate := range candidates {
+		if d := editDistance(word, candidate); d <= distance && !slices.Contains(found, candidate) {
+			found = append(found, candidate)
+			distances[candidate] = d
+		}
+	}
+	slices.SortStableFunc(found, func(a string, b string) int {
+		return distances[a] - distances[b]
+	})
+	return found
+}
+
+// the long names that an unknown long flag may have been meant as
+func (p *Parser) longSuggestions(name string) []string {
+	names := []string{}
+	for _, longname := range p.listedNames() {
+		names = append(names, p.completionLongNames(longname)...)
+	}
+	return suggestions(name, names, p.suggestion_distance)
+}
+
+// the short flags that an unknown short flag may have been meant as: those differing only in case
+func (p *Parser) shortSuggestions(short rune) []string {
+	found := []string{}
+	if p.suggestion_distance <= 0 {
+		return found
+	}
+	for _, name := range p.listedNames() {
+		for _, candidate := range p.shortFlagsFor(name) {
+			if unicode.ToLower(candidate) == unicode.ToLower(short) {
+				found = append(found, string(candidate))
+			}
+		}
+	}
+	return found
+}
+
+// add suggestions to an invalid choice error
+func (p *Parser) suggestChoices(err error) error {
+	var choice_err *InvalidChoiceError
+	if errors.As(err, &choice_err) {
+		choice_err.Suggestions = suggestions(choice_err.Value, choice_err.Choices, p.suggestion_distance)
+	}
+	return err
+}
+
+// "a", "a or b", "a, b or c"
+func alternatives(items []string, prefix string, suffix string) string {
+	quoted := []string{}
+	for _, item := range items {
+		quoted = append(quoted, prefix+item+suffix)
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return fmt.Sprintf("%s or %s", strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
@@ -0,0 +1,86 @@
+package goargs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_editDistance(t *testing.T) {
+	gocheck.Equal(t, 0, editDistance("verbose", "verbose"))
+	gocheck.Equal(t, 2, editDistance("verbsoe", "verbose"))
+	gocheck.Equal(t, 3, editDistance("", "abc"))
+	gocheck.Equal(t, 1, editDistance("colour", "color"))
+}
+
+func Test_Suggestions(t *testing.T) {
+	parser := NewParser("")
+	parser.Count("verbose", "")
+	parser.SetShortFlag('v', "verbose")
+	parser.Bool("version", false, "")
+	parser.Choices("tool", []string{"hammer", "mallet", "spanner"}, "")
+	parser.Mode("damage", "blunt", map[rune]string{'b': "blunt", 's': "sharp"}, "")
+
+	var unknown *UnknownFlagError
+	err := parser.Parse([]string{"--verbsoe"})
+	if !errors.As(err, &unknown) {
+		t.Errorf("Expected UnknownFlagError, got: %v", err)
+	} else {
+		gocheck.EqualArr(t, []string{"verbose"}, unknown.Suggestions)
+		gocheck.Equal(t, "unknown flag --verbsoe (did you mean --verbose?)", err.Error())
+	}
+
+	err = parser.Parse([]string{"-V"})
+	if errors.As(err, &unknown) {
+		gocheck.EqualArr(t, []string{"v"}, unknown.Suggestions)
+		gocheck.Equal(t, "unknown short flag 'V' (did you mean -v?)", err.Error())
+	}
+
+	var choice *InvalidChoiceError
+	err = parser.Parse([]string{"--tool", "hamer"})
+	if !errors.As(err, &choice) {
+		t.Errorf("Expected InvalidChoiceError, got: %v", err)
+	} else {
+		gocheck.EqualArr(t, []string{"hammer"}, choice.Suggestions)
+		gocheck.Equal(t, "Invalid choice 'hamer'. Valid choices: [hammer mallet spanner]. Did you mean 'hammer'?", err.Error())
+	}
+
+	err = parser.Parse([]string{"--damage=shrap"})
+	if !errors.As(err, &choice) {
+		t.Errorf("Expected InvalidChoiceError, got: %v", err)
+	} else {
+		gocheck.EqualArr(t, []string{"sharp"}, choice.Suggestions)
+		gocheck.Equal(t, "Invalid mode 'shrap' - choose from: blunt, sharp. Did you mean 'sharp'?", err.Error())
+	}
+
+	err = parser.Parse([]string{"--unrelated"})
+	if errors.As(err, &unknown) {
+		gocheck.Equal(t, 0, len(unknown.Suggestions))
+	}
+}
+
+func Test_Suggestions_Distance(t *testing.T) {
+	parser := NewParser("")
+	parser.Count("verbose", "")
+	parser.SetShortFlag('v', "verbose")
+	parser.Bool("version", false, "")
+	parser.Choices("tool", []string{"hammer", "mallet", "spanner"}, "")
+	parser.Mode("damage", "blunt", map[rune]string{'b': "blunt", 's': "sharp"}, "")
+	parser.SetSuggestionDistance(0)
+
+	var unknown *UnknownFlagError
+	if err := parser.Parse([]string{"--verbsoe"}); errors.As(err, &unknown) {
+		gocheck.Equal(t, "unknown flag --verbsoe", err.Error())
+	} else {
+		t.Errorf("Expected UnknownFlagError, got: %v", err)
+	}
+
+	parser.SetSuggestionDistance(4)
+	if err := parser.Parse([]string{"--versio"}); errors.As(err, &unknown) {
+		gocheck.EqualArr(t, []string{"version", "verbose"}, unknown.Suggestions)
+		gocheck.Equal(t, "unknown flag --versio (did you mean --version or --verbose?)", err.Error())
+	} else {
+		t.Errorf("Expected UnknownFlagError, got: %v", err)
+	}
+}
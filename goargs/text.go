@@ -0,0 +1,69 @@
+package goargs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type def_Text struct {
+	name    string
+	defval  encoding.TextMarshaler
+	value   encoding.TextUnmarshaler
+	helpstr string
+}
+
+func (self def_Text) getHelpString() string { return self.helpstr }
+func (self def_Text) getName() string       { return self.name }
+func (self def_Text) assign(value string) error {
+	return self.value.UnmarshalText([]byte(value))
+}
+func (self def_Text) reset() {
+	// the default was checked on registration
+	text, _ := self.defval.MarshalText()
+	self.value.UnmarshalText(text)
+}
+
+// the default value, as rendered by its MarshalText()
+func (self def_Text) defaultText() string {
+	text, err := self.defval.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("(%v)", err)
+	}
+	return string(text)
+}
+
+// the name of the pointed-to type, e.g. "IP" for a `*net.IP`
+func (self def_Text) typeName() string {
+	vtype := reflect.TypeOf(self.value)
+	if vtype.Kind() == reflect.Pointer {
+		vtype = vtype.Elem()
+	}
+	if vtype.Name() == "" {
+		return "VALUE"
+	}
+	return strings.ToUpper(vtype.Name())
+}
+
+/*
+Register a flag of a type implementing encoding.TextUnmarshaler, storing to the supplied `value` pointer
+The default value `defval` is marshalled and assigned to `value` immediately.
+
+e.g. `parser.TextVar(&addr, "listen", net.ParseIP("127.0.0.1"), "Address to listen on")`
+
+Panics if the default value cannot be marshalled and unmarshalled into `value`.
+*/
+func (p *Parser) TextVar(value encoding.TextUnmarshaler, name string, defval encoding.TextMarshaler, helpstr string) {
+	text, err := defval.MarshalText()
+	if err == nil {
+		err = value.UnmarshalText(text)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("Invalid default value for '--%s': %v", name, err))
+	}
+
+	vdef := def_Text{name, defval, value, helpstr}
+	p.definitions[name] = vdef
+	p.enqueueName(name)
+}
@@ -0,0 +1,50 @@
+package goargs
+
+import (
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_TextVar(t *testing.T) {
+	parser := NewParser("")
+	var addr net.IP
+	parser.TextVar(&addr, "listen", net.ParseIP("127.0.0.1"), "Address to listen on")
+	var level slog.Level
+	parser.TextVar(&level, "log-level", slog.LevelWarn, "Logging level")
+
+	gocheck.Equal(t, "127.0.0.1", addr.String())
+	gocheck.Equal(t, slog.LevelWarn, level)
+
+	if err := parser.Parse([]string{"--listen", "10.0.0.1", "--log-level", "debug"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+	gocheck.Equal(t, "10.0.0.1", addr.String())
+	gocheck.Equal(t, slog.LevelDebug, level)
+
+	if err := parser.Parse([]string{"--listen", "ten"}); err == nil {
+		t.Errorf("Invalid address should have failed, got %v", addr)
+	}
+}
+
+func Test_TextVar_Help(t *testing.T) {
+	parser := NewParser("Serve")
+	var level slog.Level
+	parser.TextVar(&level, "log-level", slog.LevelWarn, "Logging level")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"Serve",
+		"",
+		"  --log-level LEVEL",
+		"    default: WARN",
+		"    Logging level",
+	}, "\n")
+	if helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
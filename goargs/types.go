@@ -17,6 +17,7 @@ type def_String struct {
 
 func (self def_String) getHelpString() string     { return self.helpstr }
 func (self def_String) getName() string           { return self.name }
+func (self def_String) reset()                    { *self.value = self.defval }
 func (self def_String) assign(value string) error { *self.value = value; return nil }
 
 // Register a string flag, storing to the supplied `value *string` pointer
@@ -45,9 +46,10 @@ type def_Int struct {
 
 func (self def_Int) getHelpString() string { return self.helpstr }
 func (self def_Int) getName() string       { return self.name }
+func (self def_Int) reset()                { *self.value = self.defval }
 func (self def_Int) assign(value string) error {
 	if val, err := strconv.Atoi(value); err != nil {
-		return fmt.Errorf("Could not parse %s\n", value)
+		return fmt.Errorf("Could not parse %s", value)
 	} else {
 		*self.value = val
 	}
@@ -80,9 +82,10 @@ type def_Int64 struct {
 
 func (self def_Int64) getHelpString() string { return self.helpstr }
 func (self def_Int64) getName() string       { return self.name }
+func (self def_Int64) reset()                { *self.value = self.defval }
 func (self def_Int64) assign(value string) error {
 	if val, err := strconv.Atoi(value); err != nil {
-		return fmt.Errorf("Could not parse %s\n", value)
+		return fmt.Errorf("Could not parse %s", value)
 	} else {
 		*self.value = int64(val)
 	}
@@ -115,9 +118,10 @@ type def_Uint struct {
 
 func (self def_Uint) getHelpString() string { return self.helpstr }
 func (self def_Uint) getName() string       { return self.name }
+func (self def_Uint) reset()                { *self.value = self.defval }
 func (self def_Uint) assign(value string) error {
 	if val, err := strconv.Atoi(value); err != nil {
-		return fmt.Errorf("Could not parse %s\n", value)
+		return fmt.Errorf("Could not parse %s", value)
 	} else {
 		*self.value = uint(val)
 	}
@@ -150,9 +154,10 @@ type def_Float struct {
 
 func (self def_Float) getHelpString() string { return self.helpstr }
 func (self def_Float) getName() string       { return self.name }
+func (self def_Float) reset()                { *self.value = self.defval }
 func (self def_Float) assign(value string) error {
 	if val, err := strconv.ParseFloat(value, 32); err != nil {
-		return fmt.Errorf("Could not parse %s\n", value)
+		return fmt.Errorf("Could not parse %s", value)
 	} else {
 		*self.value = float32(val)
 	}
@@ -185,9 +190,10 @@ type def_Float64 struct {
 
 func (self def_Float64) getHelpString() string { return self.helpstr }
 func (self def_Float64) getName() string       { return self.name }
+func (self def_Float64) reset()                { *self.value = self.defval }
 func (self def_Float64) assign(value string) error {
 	if val, err := strconv.ParseFloat(value, 64); err != nil {
-		return fmt.Errorf("Could not parse %s\n", value)
+		return fmt.Errorf("Could not parse %s", value)
 	} else {
 		*self.value = float64(val)
 	}
@@ -220,10 +226,18 @@ type def_Bool struct {
 
 func (self def_Bool) getHelpString() string { return self.helpstr }
 func (self def_Bool) getName() string       { return self.name }
+func (self def_Bool) reset()                { *self.value = self.defval }
 func (self def_Bool) assign(value string) error {
-	panic("(goargs) Invalid call to assign() on BoolDef")
+	val, err := parseBool(value)
+	if err != nil {
+		return err
+	}
+	*self.value = val
+	return nil
 }
-func (self def_Bool) activate() { *self.value = !self.defval }
+
+// a bare Bool flag toggles its default, or always sets true if it is negatable as `--no-NAME`
+func (self def_Bool) activate(negatable bool) { *self.value = negatable || !self.defval }
 
 // Register a bool flag, storing to the supplied `value *bool` pointer
 func (p *Parser) BoolVar(value *bool, name string, defval bool, helpstr string) {
@@ -251,6 +265,7 @@ type def_Duration struct {
 
 func (self def_Duration) getHelpString() string { return self.helpstr }
 func (self def_Duration) getName() string       { return self.name }
+func (self def_Duration) reset()                { *self.value = self.defval }
 func (self def_Duration) assign(value string) error {
 	if duration, err := time.ParseDuration(value); err != nil {
 		return err
@@ -3,6 +3,7 @@ package goargs
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // Unpack arguments into a series of variables, and return any unassigned values.
@@ -10,7 +11,9 @@ import (
 //
 // `vars` are pointers to supported types.
 //
-// Supported types: *string, *int, *float32
+// Supported types: *string, *int, *float32, *bool
+//
+// Returns an *InvalidValueError if a token cannot be parsed.
 func Unpack(tokens []string, vars ...interface{}) ([]string, error) {
 	max := len(tokens)
 	if len(vars) < max {
@@ -32,43 +35,47 @@ func Unpack(tokens []string, vars ...interface{}) ([]string, error) {
 		case *int:
 			val, err := strconv.Atoi(tok)
 			if err != nil {
-				return nil, fmt.Errorf("Could not parse int %s : %v", tok, err)
+				return nil, &InvalidValueError{"", tok, fmt.Errorf("Could not parse int %s : %v", tok, err)}
 			}
 			var lab *int = label.(*int)
 			*lab = val
 		case *float32:
 			float, err := strconv.ParseFloat(tok, 32)
 			if err != nil {
-				return nil, fmt.Errorf("Could not parse int %s : %v", tok, err)
+				return nil, &InvalidValueError{"", tok, fmt.Errorf("Could not parse float %s : %v", tok, err)}
 			}
 			var lab *float32 = label.(*float32)
 			*lab = float32(float)
 		case *bool:
-			var lab *bool = label.(*bool)
-			switch tok {
-			case "false":
-				*lab = false
-			case "0":
-				*lab = false
-			case "true":
-				*lab = true
-			case "1":
-				*lab = true
-			default:
-				return nil, fmt.Errorf("Invalid string value for boolean: %v . Try 'true', 'false, '1', or '0'.", tok)
+			val, err := parseBool(tok)
+			if err != nil {
+				return nil, &InvalidValueError{"", tok, err}
 			}
+			var lab *bool = label.(*bool)
+			*lab = val
 		}
 	}
 
 	return tokens[max:], nil
 }
 
+// Parse a boolean token value
+func parseBool(tok string) (bool, error) {
+	switch strings.ToLower(tok) {
+	case "false", "0", "no":
+		return false, nil
+	case "true", "1", "yes":
+		return true, nil
+	default:
+		return false, fmt.Errorf("Invalid string value for boolean: %v . Try 'true', 'false', 'yes', 'no', '1', or '0'.", tok)
+	}
+}
+
 // Unpack tokens, expecting the number of variables and number of tokens to match.
+// Returns an *ArityMismatchError if the counts differ, or an *InvalidValueError if a token cannot be parsed.
 func UnpackExactly(tokens []string, vars ...interface{}) error {
 	if len(tokens) != len(vars) {
-		// FIXME - how does dev user differentiate a parse error from a mismatch error?
-		//         dev will surely want to handle the two cases differently...
-		return fmt.Errorf("Mismatch number of tokens (%d) to number of variables to populate (%d)", len(tokens), len(vars))
+		return &ArityMismatchError{len(vars), len(tokens)}
 	}
 
 	if _, err := Unpack(tokens, vars...); err != nil {
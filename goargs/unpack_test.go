@@ -36,3 +36,18 @@ func Test_Unpack(t *testing.T) {
 		t.Errorf("Should have failed due to insufficient tokens!")
 	}
 }
+
+func Test_Unpack_BoolWords(t *testing.T) {
+	var yes bool
+	var no bool = true
+
+	if err := UnpackExactly([]string{"yes", "No"}, &yes, &no); err != nil {
+		t.Errorf("Should have parsed OK, got error: %v", err)
+	}
+	gocheck.Equal(t, true, yes)
+	gocheck.Equal(t, false, no)
+
+	if err := UnpackExactly([]string{"maybe"}, &yes); err == nil {
+		t.Errorf("Should have failed on a non-boolean value")
+	}
+}
@@ -0,0 +1,48 @@
+package goargs
+
+// Value is the interface to a custom flag type, similar to the standard library's `flag.Value`.
+// Register a Value with Parser.Var()
+type Value interface {
+	// Parse the token and store its value, or return an error if it is invalid
+	Set(string) error
+	// Represent the current value, used as default value in the help
+	String() string
+	// Name of the value type, used in the help, e.g. "ADDRESS"
+	Type() string
+}
+
+// Resetter is optionally implemented by a Value to restore its default on Parser.Reset().
+// Values without it keep their current contents across parses, as `Set()` may accumulate (e.g. lists).
+type Resetter interface {
+	Reset()
+}
+
+type def_Value struct {
+	name    string
+	defval  string
+	value   Value
+	helpstr string
+}
+
+func (self def_Value) getHelpString() string     { return self.helpstr }
+func (self def_Value) getName() string           { return self.name }
+func (self def_Value) assign(value string) error { return self.value.Set(value) }
+func (self def_Value) reset() {
+	if resetter, ok := self.value.(Resetter); ok {
+		resetter.Reset()
+	}
+}
+
+// Register a flag of a custom type, storing to the supplied `value Value`
+// The current value of `value` is used as default. Parser.Reset() only restores it if `value` is a Resetter.
+func (p *Parser) Var(value Value, name string, helpstr string) {
+	vdef := def_Value{name, value.String(), value, helpstr}
+	p.definitions[name] = vdef
+	p.enqueueName(name)
+}
+
+// Register a positional of a custom type, storing to the supplied `value Value`
+// The current value of `value` is kept as default, if the positional is optional.
+func (p *Parser) PositionalVar(value Value, name string, helpstr string) {
+	p.enqueuePositional(def_Value{name, value.String(), value, helpstr}, false)
+}
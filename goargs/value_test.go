@@ -0,0 +1,92 @@
+package goargs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+type t_Point struct {
+	x int
+	y int
+}
+
+func (pt *t_Point) Set(value string) error {
+	if _, err := fmt.Sscanf(value, "%d,%d", &pt.x, &pt.y); err != nil {
+		return fmt.Errorf("expected X,Y : %v", err)
+	}
+	return nil
+}
+func (pt *t_Point) String() string { return fmt.Sprintf("%d,%d", pt.x, pt.y) }
+func (pt *t_Point) Type() string   { return "point" }
+
+func Test_Value(t *testing.T) {
+	parser := NewParser("")
+	origin := t_Point{1, 2}
+	parser.Var(&origin, "origin", "Start point")
+	parser.SetShortFlag('o', "origin")
+	var target t_Point
+	parser.PositionalVar(&target, "TARGET", "End point")
+
+	if err := parser.Parse([]string{"-o", "3,4", "5,6"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+	gocheck.Equal(t, t_Point{3, 4}, origin)
+	gocheck.Equal(t, t_Point{5, 6}, target)
+
+	if err := parser.Parse([]string{"--origin", "nowhere"}); err == nil {
+		t.Errorf("Invalid point should have failed, got %v", origin)
+	}
+}
+
+func Test_Value_Help(t *testing.T) {
+	parser := NewParser("Move")
+	parser.Var(&t_Point{1, 2}, "origin", "Start point")
+	parser.SetShortFlag('o', "origin")
+
+	helptext := parser.SPrintHelp()
+	expect := strings.Join([]string{
+		"Move",
+		"",
+		"  --origin POINT",
+		"  -o POINT",
+		"    default: 1,2",
+		"    Start point",
+	}, "\n")
+	if helptext != expect {
+		t.Errorf("Mismatched help strings. Got:\n<<%s>>\nInstead of:\n<<%s>>", helptext, expect)
+	}
+}
+
+type t_Tags []string
+
+func (tags *t_Tags) Set(value string) error { *tags = append(*tags, value); return nil }
+func (tags *t_Tags) String() string         { return strings.Join(*tags, ",") }
+func (tags *t_Tags) Type() string           { return "tag" }
+
+type t_ResetTags struct{ t_Tags }
+
+func (tags *t_ResetTags) Reset() { tags.t_Tags = nil }
+
+func Test_Value_Reset(t *testing.T) {
+	parser := NewParser("")
+	var tags t_Tags
+	parser.Var(&tags, "tag", "Tags to apply")
+	var resettable t_ResetTags
+	parser.Var(&resettable, "label", "Labels to apply")
+
+	if err := parser.Parse([]string{"--tag", "a", "--label", "x"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+	if err := parser.Parse([]string{"--tag", "b", "--label", "y"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+		return
+	}
+	// a Value without Reset() is left as-is, rather than fed its default again
+	gocheck.EqualArr(t, []string{"a", "b"}, tags)
+	gocheck.EqualArr(t, []string{"y"}, resettable.t_Tags)
+}
@@ -0,0 +1,93 @@
+package goargs
+
+import (
+	"fmt"
+	"slices"
+)
+
+type ValueSource int
+
+const (
+	// The flag kept its default value
+	SOURCE_DEFAULT ValueSource = iota
+	// The flag was found in the tokens given to Parse()
+	SOURCE_CLI
+	// The flag was assigned from its environment variable
+	SOURCE_ENV
+	// The flag was assigned from loaded configuration values
+	SOURCE_CONFIG
+)
+
+// How a flag was set during parsing
+type t_FlagState struct {
+	source  ValueSource
+	indices []int
+}
+
+// FlagInfo describes a flag, and how it was set during the last Parse(), for Visit() and VisitAll()
+type FlagInfo struct {
+	// The long name of the flag
+	Name string
+	// The help string of the flag
+	Help string
+	// The default value as shown in help, empty for flags without a default
+	Default string
+	// Where the flag's value came from
+	Source ValueSource
+	// The number of times the flag was found in the tokens
+	Count int
+	// The indices of the tokens the flag was found in, within the tokens given to the top-level Parse()
+	Indices []int
+}
+
+// record that a flag was set, from the token at `index` if on the command line
+func (p *Parser) markSet(seen map[string]bool, name string, source ValueSource, index int) {
+	seen[name] = true
+	state, ok := p.flag_states[name]
+	if !ok {
+		state = &t_FlagState{}
+		p.flag_states[name] = state
+	}
+	state.source = source
+	if source == SOURCE_CLI {
+		state.indices = append(state.indices, index)
+	}
+}
+
+// Whether a flag was set during the last Parse(), from the command line, environment or configuration.
+// The flag can be named by its long name or one of its aliases. Panics if a long flag is not yet registered.
+func (p *Parser) Changed(name string) bool {
+	return p.flagInfo(name).Source != SOURCE_DEFAULT
+}
+
+func (p *Parser) flagInfo(name string) FlagInfo {
+	def, ok := p.lookupFlag(name)
+	if !ok {
+		panic(fmt.Sprintf("Flag '--%s' not yet defined", name))
+	}
+	name = def.getName()
+	info := FlagInfo{Name: name, Help: def.getHelpString()}
+	info.Default, _ = defaultString(def)
+	if state, ok := p.flag_states[name]; ok {
+		info.Source = state.source
+		info.Count = len(state.indices)
+		info.Indices = slices.Clone(state.indices)
+	}
+	return info
+}
+
+// Call `fn` for each flag set during the last Parse(), in declaration order
+func (p *Parser) Visit(fn func(FlagInfo)) {
+	for _, name := range p.longnames {
+		if info := p.flagInfo(name); info.Source != SOURCE_DEFAULT {
+			fn(info)
+		}
+	}
+}
+
+// Call `fn` for every flag, in declaration order
+func (p *Parser) VisitAll(fn func(FlagInfo)) {
+	for _, name := range p.longnames {
+		fn(p.flagInfo(name))
+	}
+}
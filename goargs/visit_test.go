@@ -0,0 +1,76 @@
+package goargs
+
+import (
+	"testing"
+
+	"github.com/taikedz/gocheck"
+)
+
+func Test_Changed(t *testing.T) {
+	parser := NewParser("")
+	parser.Int("port", 8080, "Port")
+	parser.String("host", "localhost", "Host")
+	parser.SetEnvVar("host", "VISIT_HOST")
+	parser.Count("verbose", "Verbosity")
+	parser.SetShortFlag('v', "verbose")
+	parser.String("user", "", "User")
+	db := parser.AddCommand("db", "")
+	db.Bool("dry-run", false, "")
+	t.Setenv("VISIT_HOST", "example.com")
+
+	if err := parser.Parse([]string{"--port", "8080", "-vv", "--verbose", "db", "--dry-run"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, true, parser.Changed("port"))
+	gocheck.Equal(t, true, parser.Changed("host"))
+	gocheck.Equal(t, true, parser.Changed("verbose"))
+	gocheck.Equal(t, false, parser.Changed("user"))
+	gocheck.Equal(t, true, db.Changed("dry-run"))
+
+	visited := []FlagInfo{}
+	parser.Visit(func(info FlagInfo) {
+		visited = append(visited, info)
+	})
+	gocheck.Equal(t, 3, len(visited))
+	gocheck.Equal(t, "port", visited[0].Name)
+	gocheck.Equal(t, SOURCE_CLI, visited[0].Source)
+	gocheck.Equal(t, "8080", visited[0].Default)
+	gocheck.EqualArr(t, []int{0}, visited[0].Indices)
+	gocheck.Equal(t, "host", visited[1].Name)
+	gocheck.Equal(t, SOURCE_ENV, visited[1].Source)
+	gocheck.Equal(t, 0, visited[1].Count)
+	gocheck.Equal(t, "verbose", visited[2].Name)
+	gocheck.Equal(t, 3, visited[2].Count)
+	gocheck.EqualArr(t, []int{2, 2, 3}, visited[2].Indices)
+
+	db.Visit(func(info FlagInfo) {
+		gocheck.Equal(t, "dry-run", info.Name)
+		gocheck.EqualArr(t, []int{5}, info.Indices)
+	})
+
+	names := []string{}
+	parser.VisitAll(func(info FlagInfo) {
+		names = append(names, info.Name)
+	})
+	gocheck.EqualArr(t, []string{"port", "host", "verbose", "user"}, names)
+
+	// a fresh parse forgets previous assignments
+	if err := parser.Parse([]string{"--user", "alex"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, false, parser.Changed("port"))
+	gocheck.Equal(t, true, parser.Changed("user"))
+	gocheck.Equal(t, false, db.Changed("dry-run"))
+}
+
+func Test_Changed_Alias(t *testing.T) {
+	parser := NewParser("")
+	parser.String("directory", ".", "Where to work")
+	parser.SetAlias("dir", "directory")
+
+	if err := parser.Parse([]string{"--dir", "/tmp"}); err != nil {
+		t.Errorf("Failed parse: %v", err)
+	}
+	gocheck.Equal(t, true, parser.Changed("dir"))
+	gocheck.Equal(t, true, parser.Changed("directory"))
+}